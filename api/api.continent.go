@@ -4,14 +4,14 @@ import (
 	"context"
 	"corona/helpers"
 	"corona/models"
-	"database/sql"
+	"corona/storage"
 	uuid "github.com/satori/go.uuid"
 	"net/http"
 )
 
 type (
 	ContinentModule struct {
-		db     *sql.DB
+		store  storage.Store
 		logger *helpers.Logger
 		name   string
 	}
@@ -30,9 +30,9 @@ type (
 	}
 )
 
-func NewContinentModule(db *sql.DB, logger *helpers.Logger) *ContinentModule {
+func NewContinentModule(store storage.Store, logger *helpers.Logger) *ContinentModule {
 	return &ContinentModule{
-		db:     db,
+		store:  store,
 		logger: logger,
 		name:   "module/continent",
 	}
@@ -40,11 +40,16 @@ func NewContinentModule(db *sql.DB, logger *helpers.Logger) *ContinentModule {
 
 func (s ContinentModule) List(ctx context.Context, filter helpers.Filter) (interface{}, *helpers.Error) {
 
-	continents, err := models.GetAllContinent(ctx, s.db, filter)
+	continents, err := s.store.Continent.GetAll(ctx, filter)
 
 	if err != nil {
-		return nil, helpers.ErrorWrap(err, s.name, "List/GetAllContinent",
-			helpers.InternalServerError, http.StatusInternalServerError)
+		return nil, helpers.FilterErrorWrap(err, s.name, "List/GetAllContinent")
+	}
+
+	total, err := s.store.Continent.Count(ctx, filter)
+
+	if err != nil {
+		return nil, helpers.FilterErrorWrap(err, s.name, "List/CountContinent")
 	}
 
 	var continentResponses []models.ContinentResponse
@@ -52,12 +57,17 @@ func (s ContinentModule) List(ctx context.Context, filter helpers.Filter) (inter
 		continentResponses = append(continentResponses, continent.Response())
 	}
 
-	return continentResponses, nil
+	page := helpers.NewPage(continentResponses, filter, len(continents), total)
+	if page.More() {
+		page.Cursor = continents[len(continents)-1].Cursor(filter)
+	}
+
+	return page, nil
 }
 
 func (s ContinentModule) Detail(ctx context.Context, param ContinentDetailParam) (interface{}, *helpers.Error) {
 
-	continent, err := models.GetOneContinent(ctx, s.db, param.Id)
+	continent, err := s.store.Continent.GetOne(ctx, param.Id)
 
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, s.name, "Detail/GetOneContinent",
@@ -80,7 +90,7 @@ func (s ContinentModule) Add(ctx context.Context, param ContinentAddParam) (inte
 			CreatedBy: uuid.NewV4(),
 		}
 
-		err := continent.Insert(ctx, s.db)
+		err := s.store.Continent.Insert(ctx, &continent)
 		if err != nil {
 			return nil, helpers.ErrorWrap(err, s.name, "Add/Insert", helpers.InternalServerError,
 				http.StatusInternalServerError)
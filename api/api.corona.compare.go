@@ -0,0 +1,222 @@
+package api
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"database/sql"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+)
+
+type (
+	CompareParam struct {
+		Countries []string  `json:"countries"`
+		Metrics   []string  `json:"metrics"`
+		From      time.Time `json:"from"`
+		To        time.Time `json:"to"`
+	}
+
+	// CoronaComparison lines several countries up: Values holds, per metric, the
+	// current value of each of Countries, in the same order.
+	CoronaComparison struct {
+		Countries []models.CountryResponse `json:"countries"`
+		Metrics   []string                 `json:"metrics"`
+		Values    map[string][]*float64    `json:"values"`
+		Timeline  *ComparisonTimeline      `json:"timeline,omitempty"`
+	}
+
+	// ComparisonTimeline holds, per metric, a row per country of its value on
+	// each of Dates, null on the days it has no snapshot.
+	ComparisonTimeline struct {
+		From   string                  `json:"from"`
+		To     string                  `json:"to"`
+		Dates  []string                `json:"dates"`
+		Series map[string][][]*float64 `json:"series"`
+	}
+)
+
+const maxCompareCountries = 10
+
+// isoCode matches the names that may be an ISO alpha-2 or alpha-3 code.
+var isoCode = regexp.MustCompile(`^[A-Za-z]{2,3}$`)
+
+// Compare returns the figures and derived values of a few countries, given by
+// ISO code or name, side by side, with their timelines over the range when they
+// have snapshots. Metrics default to every figure and derived value.
+func (s CoronaModule) Compare(ctx context.Context, param CompareParam) (interface{}, *helpers.Error) {
+
+	if len(param.Countries) == 0 || len(param.Countries) > maxCompareCountries {
+		return nil, helpers.ErrorWrap(errors.Errorf("compare between 1 and %d countries", maxCompareCountries),
+			s.name, "Compare/Countries", helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	if len(param.Metrics) == 0 {
+		param.Metrics = append(append([]string{}, models.CoronaMetrics...), models.DerivedMetrics...)
+	}
+
+	for _, metric := range param.Metrics {
+		if !models.IsRankingMetric(metric) {
+			return nil, helpers.ErrorWrap(errors.Errorf("unknown metric %q", metric), s.name, "Compare/Metrics",
+				helpers.BadRequestMessage, http.StatusBadRequest)
+		}
+	}
+
+	if param.To.IsZero() {
+		param.To = time.Now().UTC()
+	}
+
+	if param.From.IsZero() {
+		param.From = param.To.AddDate(0, 0, -defaultTimelineDays)
+	}
+
+	if err := checkRange(param.From, param.To); err != nil {
+		return nil, helpers.ErrorWrap(err, s.name, "Compare/Range",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	var (
+		countries []models.CountryDetailModel
+		ids       []uuid.UUID
+		seen      = make(map[uuid.UUID]bool)
+	)
+	for _, name := range param.Countries {
+
+		country, err := s.lookupCountry(ctx, name)
+
+		if err != nil {
+			if err == sql.ErrNoRows {
+				return nil, helpers.ErrorWrap(errors.Wrapf(err, "country %q", name), s.name,
+					"Compare/GetOneCountry", helpers.NotFoundMessage, http.StatusNotFound)
+			}
+			return nil, helpers.ErrorWrap(err, s.name, "Compare/GetOneCountry",
+				helpers.InternalServerError, http.StatusInternalServerError)
+		}
+
+		if seen[country.Country.Id] {
+			continue
+		}
+
+		seen[country.Country.Id] = true
+		countries = append(countries, country)
+		ids = append(ids, country.Country.Id)
+	}
+
+	data, err := s.store.Corona.GetAllByCountries(ctx, ids)
+
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, s.name, "Compare/GetAllCoronaByCountries",
+			helpers.InternalServerError, http.StatusInternalServerError)
+	}
+
+	figures := make(map[uuid.UUID]models.CoronaModel, len(data))
+	for _, detail := range data {
+		figures[detail.Country.Country.Id] = detail.Corona
+	}
+
+	comparison := CoronaComparison{
+		Metrics: param.Metrics,
+		Values:  make(map[string][]*float64),
+	}
+
+	for _, country := range countries {
+		comparison.Countries = append(comparison.Countries, country.Response())
+	}
+
+	for _, metric := range param.Metrics {
+
+		values := make([]*float64, len(countries))
+
+		for i, country := range countries {
+			if corona, ok := figures[country.Country.Id]; ok {
+				values[i] = corona.MetricValue(metric)
+			}
+		}
+
+		comparison.Values[metric] = values
+	}
+
+	var (
+		snapshots = make([]map[string]models.CoronaSnapshotModel, len(countries))
+		days      = make(map[string]bool)
+	)
+	for i, country := range countries {
+
+		all, err := s.store.Corona.GetAllSnapshotByCountry(ctx, country.Country.Id, param.From, param.To)
+
+		if err != nil {
+			return nil, helpers.ErrorWrap(err, s.name, "Compare/GetAllCoronaSnapshotByCountry",
+				helpers.InternalServerError, http.StatusInternalServerError)
+		}
+
+		snapshots[i] = make(map[string]models.CoronaSnapshotModel, len(all))
+
+		for _, snapshot := range all {
+
+			date := snapshot.ReportDate.Format(helpers.DateLayout)
+
+			days[date] = true
+			snapshots[i][date] = snapshot
+		}
+	}
+
+	if len(days) == 0 {
+		return comparison, nil
+	}
+
+	dates := make([]string, 0, len(days))
+	for date := range days {
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	timeline := &ComparisonTimeline{
+		From:   param.From.Format(helpers.DateLayout),
+		To:     param.To.Format(helpers.DateLayout),
+		Dates:  dates,
+		Series: make(map[string][][]*float64),
+	}
+
+	for _, metric := range param.Metrics {
+
+		rows := make([][]*float64, len(countries))
+
+		for i := range countries {
+
+			rows[i] = make([]*float64, len(dates))
+
+			for j, date := range dates {
+				if snapshot, ok := snapshots[i][date]; ok {
+					rows[i][j] = snapshot.MetricValue(metric)
+				}
+			}
+		}
+
+		timeline.Series[metric] = rows
+	}
+
+	comparison.Timeline = timeline
+
+	return comparison, nil
+
+}
+
+// lookupCountry finds a country by ISO code when name may be one, and by name
+// otherwise or when no country has that code.
+func (s CoronaModule) lookupCountry(ctx context.Context, name string) (models.CountryDetailModel, error) {
+
+	if isoCode.MatchString(name) {
+
+		country, err := s.store.Country.GetOneByCode(ctx, name)
+
+		if err != sql.ErrNoRows {
+			return country, err
+		}
+	}
+
+	return s.store.Country.GetOneByName(ctx, name)
+}
@@ -5,16 +5,20 @@ import (
 	"corona/helpers"
 	"corona/models"
 	"corona/scrapper"
+	"corona/storage"
 	"database/sql"
+	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"net/http"
+	"time"
 )
 
 type (
 	CoronaModule struct {
-		db     *sql.DB
-		logger *helpers.Logger
-		name   string
+		store    storage.Store
+		scrapper *scrapper.Scrapper
+		logger   *helpers.Logger
+		name     string
 	}
 
 	CoronaDetailParam struct {
@@ -28,171 +32,420 @@ type (
 	ByContinentParam struct {
 		Continent string `json:"continent"`
 	}
+
+	ContinentCorona struct {
+		Totals    models.CoronaTotalResponse `json:"totals"`
+		Countries []models.CoronaResponse    `json:"countries"`
+	}
+
+	TimelineParam struct {
+		Country string    `json:"country"`
+		From    time.Time `json:"from"`
+		To      time.Time `json:"to"`
+		Metrics []string  `json:"metrics"`
+	}
+
+	TimelinePoint struct {
+		Date  string `json:"date"`
+		Value *int64 `json:"value"`
+	}
+
+	TopParam struct {
+		Metric    string `json:"metric"`
+		Limit     int    `json:"n"`
+		Continent string `json:"continent"`
+	}
+
+	CoronaRank struct {
+		Rank    int                    `json:"rank"`
+		Country models.CountryResponse `json:"country"`
+		Value   *float64               `json:"value"`
+	}
+
+	CoronaTop struct {
+		Metric  string       `json:"metric"`
+		Ranking []CoronaRank `json:"ranking"`
+	}
+
+	CountryTimeline struct {
+		Country models.CountryResponse     `json:"country"`
+		From    string                     `json:"from"`
+		To      string                     `json:"to"`
+		Series  map[string][]TimelinePoint `json:"series"`
+	}
 )
 
-func NewCoronaModule(db *sql.DB, logger *helpers.Logger) *CoronaModule {
+const (
+	defaultTimelineDays = 30
+	maxTimelineDays     = 366
+
+	defaultTopLimit = 10
+	maxTopLimit     = 100
+)
+
+// Rows are the countries, the totals being left out of flat formats.
+func (s ContinentCorona) Rows() interface{} {
+	return s.Countries
+}
+
+func NewCoronaModule(store storage.Store, scrapper *scrapper.Scrapper, logger *helpers.Logger) *CoronaModule {
 	return &CoronaModule{
-		db:     db,
-		logger: logger,
-		name:   "module/corona",
+		store:    store,
+		scrapper: scrapper,
+		logger:   logger,
+		name:     "module/corona",
 	}
 }
 
 func (s CoronaModule) List(ctx context.Context, filter helpers.Filter) (interface{}, *helpers.Error) {
 
-	datas, err := models.GetAllCorona(ctx, s.db, filter)
+	datas, err := s.store.Corona.GetAll(ctx, filter)
 
 	if err != nil {
-		return nil, helpers.ErrorWrap(err, s.name, "List/GetAllCoronaData", helpers.InternalServerError,
-			http.StatusInternalServerError)
+		return nil, helpers.FilterErrorWrap(err, s.name, "List/GetAllCoronaData")
+	}
+
+	total, err := s.store.Corona.Count(ctx, filter)
+
+	if err != nil {
+		return nil, helpers.FilterErrorWrap(err, s.name, "List/CountCoronaData")
 	}
 
 	var dataResponses []models.CoronaResponse
 	for _, data := range datas {
+		dataResponses = append(dataResponses, data.Response())
+	}
 
-		response, err := data.Response(ctx, s.db, s.logger)
-		if err != nil {
-			return nil, helpers.ErrorWrap(err, s.name, "List/Response", helpers.InternalServerError,
-				http.StatusInternalServerError)
-		}
-		dataResponses = append(dataResponses, response)
-
+	page := helpers.NewPage(dataResponses, filter, len(datas), total)
+	if page.More() {
+		page.Cursor = datas[len(datas)-1].Cursor(filter)
 	}
 
-	return dataResponses, nil
+	return page, nil
 }
 
+// Add scrapes the latest figures and stores them the way UpdateAll does, so a
+// failed scrape leaves every country as it was, and returns the summary.
 func (s CoronaModule) Add(ctx context.Context) (interface{}, *helpers.Error) {
 
-	datas, err := scrapper.GetCoronaData(ctx)
+	summary, err := s.UpdateAll(ctx)
 
 	if err != nil {
-		return nil, helpers.ErrorWrap(err, s.name, "Add/GetCoronaData", helpers.InternalServerError,
+		return nil, helpers.ErrorWrap(err, s.name, "Add/UpdateAll", helpers.InternalServerError,
 			http.StatusInternalServerError)
 	}
 
-	var dataResponses []models.CoronaResponse
+	return summary, nil
 
-	for _, data := range datas {
+}
 
-		data := models.CoronaModel{
-			CountryId:      data.CountryId,
-			TotalCases:     data.TotalCases,
-			NewCases:       data.NewCases,
-			TotalDeaths:    data.TotalDeaths,
-			NewDeaths:      data.NewDeaths,
-			TotalRecovered: data.TotalRecovered,
-			ActiveCases:    data.ActiveCases,
-			SeriousCases:   data.SeriousCases,
-			TotalTests:     data.TotalTests,
-			Population:     data.Population,
-			CreatedBy:      uuid.NewV4(),
-		}
+// UpdateAll scrapes the latest figures and stores them, along with the day's
+// snapshots and world totals, in a single transaction: either every country is
+// updated or none is. The summary counts what happened to the corona_data rows.
+func (s CoronaModule) UpdateAll(ctx context.Context) (models.UpsertSummary, error) {
 
-		err := data.Insert(ctx, s.db)
-		if err != nil {
-			return nil, helpers.ErrorWrap(err, s.name, "Add/Insert", helpers.InternalServerError,
-				http.StatusInternalServerError)
-		}
+	var summary models.UpsertSummary
 
-		response, err := data.Response(ctx, s.db, s.logger)
-		if err != nil {
-			return nil, helpers.ErrorWrap(err, s.name, "Add/Response", helpers.InternalServerError,
-				http.StatusInternalServerError)
-		}
+	coronaData, err := s.scrapper.GetCoronaData(ctx)
 
-		dataResponses = append(dataResponses, response)
+	if err != nil {
+		return summary, err
 	}
 
-	return dataResponses, nil
+	reportDate := time.Now().UTC()
+	updatedBy := uuid.NewV4()
 
-}
+	err = s.store.Transaction(ctx, func(tx storage.Store) error {
 
-func UpdateAllCorona(ctx context.Context) error {
+		for _, data := range coronaData.Countries {
 
-	datas, err := scrapper.GetCoronaData(ctx)
+			data.CreatedBy = updatedBy
 
-	if err != nil {
-		return err
-	}
+			result, err := tx.Corona.Upsert(ctx, &data)
 
-	for _, data := range datas {
+			if err != nil {
+				return errors.Wrapf(err, "upsert corona data of country %s", data.CountryId)
+			}
+
+			summary.Add(result)
+
+			snapshot := models.NewCoronaSnapshot(data, reportDate)
+			snapshot.CreatedBy = updatedBy
+
+			err = tx.Corona.UpsertSnapshot(ctx, &snapshot)
+
+			if err != nil {
+				return errors.Wrapf(err, "upsert snapshot of country %s", data.CountryId)
+			}
 
-		data := models.CoronaModel{
-			CountryId:      data.CountryId,
-			TotalCases:     data.TotalCases,
-			NewCases:       data.NewCases,
-			TotalDeaths:    data.TotalDeaths,
-			NewDeaths:      data.NewDeaths,
-			TotalRecovered: data.TotalRecovered,
-			ActiveCases:    data.ActiveCases,
-			SeriousCases:   data.SeriousCases,
-			TotalTests:     data.TotalTests,
-			Population:     data.Population,
-			UpdatedBy: uuid.NullUUID{
-				UUID:  uuid.NewV4(),
-				Valid: true,
-			},
 		}
 
-		err := data.Update(ctx, dbPool)
+		world := coronaData.World
+		world.ReportDate = reportDate
+		world.CreatedBy = updatedBy
+
+		err := tx.Corona.UpsertWorldSummary(ctx, &world)
 
 		if err != nil {
-			return err
+			return errors.Wrap(err, "upsert world summary")
 		}
 
+		return nil
+	})
+
+	if err != nil {
+		return models.UpsertSummary{}, err
 	}
 
-	return nil
+	return summary, nil
+}
+
+func (s CoronaModule) World(ctx context.Context) (interface{}, *helpers.Error) {
+
+	summary, err := s.store.Corona.GetLatestWorldSummary(ctx)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helpers.ErrorWrap(err, s.name, "World/GetLatestWorldSummary",
+				helpers.NotFoundMessage, http.StatusNotFound)
+		}
+		return nil, helpers.ErrorWrap(err, s.name, "World/GetLatestWorldSummary",
+			helpers.InternalServerError, http.StatusInternalServerError)
+	}
+
+	return summary.Response(), nil
+
 }
 
 func (s CoronaModule) ByCountry(ctx context.Context, param ByCountryParam) (interface{}, *helpers.Error) {
 
-	data, err := models.GetCoronaByCountry(ctx, s.db, param.Country)
+	data, err := s.store.Corona.GetByCountry(ctx, param.Country)
 
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, s.name, "ByCountry/GetCoronaDataByCountry",
 			helpers.InternalServerError, http.StatusInternalServerError)
 	}
 
-	response, err := data.Response(ctx, s.db, s.logger)
-
-	return response, nil
+	return data.Response(), nil
 
 }
 
 func (s CoronaModule) ByContinent(ctx context.Context, filter helpers.Filter, param ByContinentParam) (
 	interface{}, *helpers.Error) {
 
-	continent, err := models.GetOneContinentByName(ctx, s.db, param.Continent)
+	continent, err := s.store.Continent.GetOneByName(ctx, param.Continent)
 
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, s.name, "ByContinent/GetOneContinentByName",
 			helpers.InternalServerError, http.StatusInternalServerError)
 	}
 
-	datas, err := models.GetAllCoronaByContinent(ctx, s.db, filter, continent.Id)
+	total, err := s.store.Corona.GetTotalByContinent(ctx, continent.Id)
 
 	if err != nil {
-		return nil, helpers.ErrorWrap(err, s.name, "ByContinent/GetAllCoronaByContinent",
+		return nil, helpers.ErrorWrap(err, s.name, "ByContinent/GetCoronaTotalByContinent",
 			helpers.InternalServerError, http.StatusInternalServerError)
 	}
 
+	datas, err := s.store.Corona.GetAllByContinent(ctx, filter, continent.Id)
+
+	if err != nil {
+		return nil, helpers.FilterErrorWrap(err, s.name, "ByContinent/GetAllCoronaByContinent")
+	}
+
+	count, err := s.store.Corona.CountByContinent(ctx, filter, continent.Id)
+
+	if err != nil {
+		return nil, helpers.FilterErrorWrap(err, s.name, "ByContinent/CountCoronaByContinent")
+	}
+
 	var responses []models.CoronaResponse
 
 	for _, data := range datas {
+		responses = append(responses, data.Response())
+	}
+
+	page := helpers.NewPage(ContinentCorona{
+		Totals:    total.Response(),
+		Countries: responses,
+	}, filter, len(datas), count)
+	if page.More() {
+		page.Cursor = datas[len(datas)-1].Cursor(filter)
+	}
+
+	return page, nil
+
+}
+
+func (s CoronaModule) Continents(ctx context.Context) (interface{}, *helpers.Error) {
 
-		response, err := data.Response(ctx, s.db, s.logger)
+	totals, err := s.store.Corona.GetAllTotal(ctx)
+
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, s.name, "Continents/GetAllCoronaTotal",
+			helpers.InternalServerError, http.StatusInternalServerError)
+	}
+
+	var responses []models.CoronaTotalResponse
+	for _, total := range totals {
+		responses = append(responses, total.Response())
+	}
+
+	return responses, nil
+
+}
+
+// Top ranks the countries, of a continent when one is given, by the value of a
+// metric, highest first. Countries for which it is unknown are left out.
+func (s CoronaModule) Top(ctx context.Context, param TopParam) (interface{}, *helpers.Error) {
+
+	if param.Metric == "" {
+		param.Metric = models.MetricTotalCases
+	}
+
+	if !models.IsRankingMetric(param.Metric) {
+		return nil, helpers.ErrorWrap(errors.Errorf("unknown metric %q", param.Metric), s.name, "Top/Metric",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	if param.Limit == 0 {
+		param.Limit = defaultTopLimit
+	}
+
+	if param.Limit < 0 || param.Limit > maxTopLimit {
+		return nil, helpers.ErrorWrap(errors.Errorf("n must be between 1 and %d", maxTopLimit), s.name, "Top/Limit",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	var continentId uuid.NullUUID
+
+	if param.Continent != "" {
+
+		continent, err := s.store.Continent.GetOneByName(ctx, param.Continent)
 
 		if err != nil {
-			return nil, helpers.ErrorWrap(err, s.name, "ByContinent/Response",
+			if err == sql.ErrNoRows {
+				return nil, helpers.ErrorWrap(err, s.name, "Top/GetOneContinentByName",
+					helpers.NotFoundMessage, http.StatusNotFound)
+			}
+			return nil, helpers.ErrorWrap(err, s.name, "Top/GetOneContinentByName",
 				helpers.InternalServerError, http.StatusInternalServerError)
 		}
 
-		responses = append(responses, response)
+		continentId = uuid.NullUUID{UUID: continent.Id, Valid: true}
+	}
+
+	datas, err := s.store.Corona.GetTop(ctx, param.Metric, param.Limit, continentId)
 
+	if err != nil {
+		return nil, helpers.FilterErrorWrap(err, s.name, "Top/GetTopCorona")
 	}
 
-	return responses, nil
+	top := CoronaTop{
+		Metric:  param.Metric,
+		Ranking: make([]CoronaRank, 0, len(datas)),
+	}
+
+	for i, data := range datas {
+		top.Ranking = append(top.Ranking, CoronaRank{
+			Rank:    i + 1,
+			Country: data.Country.Response(),
+			Value:   data.Corona.MetricValue(param.Metric),
+		})
+	}
 
+	return top, nil
+
+}
+
+// Timeline returns the daily series of the requested metrics for a country, by
+// default over the last defaultTimelineDays days.
+func (s CoronaModule) Timeline(ctx context.Context, param TimelineParam) (interface{}, *helpers.Error) {
+
+	if param.To.IsZero() {
+		param.To = time.Now().UTC()
+	}
+
+	if param.From.IsZero() {
+		param.From = param.To.AddDate(0, 0, -defaultTimelineDays)
+	}
+
+	if err := checkRange(param.From, param.To); err != nil {
+		return nil, helpers.ErrorWrap(err, s.name, "Timeline/Range",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	if len(param.Metrics) == 0 {
+		param.Metrics = models.CoronaMetrics
+	}
+
+	for _, metric := range param.Metrics {
+		if !models.IsCoronaMetric(metric) {
+			return nil, helpers.ErrorWrap(errors.Errorf("unknown metric %q", metric), s.name, "Timeline/Metrics",
+				helpers.BadRequestMessage, http.StatusBadRequest)
+		}
+	}
+
+	country, err := s.store.Country.GetOneByName(ctx, param.Country)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helpers.ErrorWrap(err, s.name, "Timeline/GetOneCountryByName",
+				helpers.NotFoundMessage, http.StatusNotFound)
+		}
+		return nil, helpers.ErrorWrap(err, s.name, "Timeline/GetOneCountryByName",
+			helpers.InternalServerError, http.StatusInternalServerError)
+	}
+
+	snapshots, err := s.store.Corona.GetAllSnapshotByCountry(ctx, country.Country.Id, param.From, param.To)
+
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, s.name, "Timeline/GetAllCoronaSnapshotByCountry",
+			helpers.InternalServerError, http.StatusInternalServerError)
+	}
+
+	timeline := CountryTimeline{
+		Country: country.Response(),
+		From:    param.From.Format(helpers.DateLayout),
+		To:      param.To.Format(helpers.DateLayout),
+		Series:  make(map[string][]TimelinePoint),
+	}
+
+	for _, metric := range param.Metrics {
+
+		points := make([]TimelinePoint, 0, len(snapshots))
+
+		for _, snapshot := range snapshots {
+
+			point := TimelinePoint{
+				Date: snapshot.ReportDate.Format(helpers.DateLayout),
+			}
+
+			if value := snapshot.Metric(metric); value.Valid {
+				point.Value = &value.Int64
+			}
+
+			points = append(points, point)
+		}
+
+		timeline.Series[metric] = points
+	}
+
+	return timeline, nil
+
+}
+
+// checkRange rejects the date ranges that end before they start or span more
+// than maxTimelineDays days, which would be read and served a day at a time.
+func checkRange(from, to time.Time) error {
+
+	if from.After(to) {
+		return errors.New("from is after to")
+	}
+
+	if from.AddDate(0, 0, maxTimelineDays).Before(to) {
+		return errors.Errorf("the range spans more than %d days", maxTimelineDays)
+	}
+
+	return nil
 }
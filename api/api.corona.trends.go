@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"database/sql"
+	"github.com/go-gota/gota/series"
+	"math"
+	"net/http"
+	"time"
+)
+
+type (
+	TrendsParam struct {
+		Country string    `json:"country"`
+		From    time.Time `json:"from"`
+		To      time.Time `json:"to"`
+	}
+
+	TrendPoint struct {
+		Date            string   `json:"date"`
+		NewCases        *float64 `json:"new_cases"`
+		NewDeaths       *float64 `json:"new_deaths"`
+		NewCasesAvg7d   *float64 `json:"new_cases_avg_7d"`
+		NewCasesAvg14d  *float64 `json:"new_cases_avg_14d"`
+		NewDeathsAvg7d  *float64 `json:"new_deaths_avg_7d"`
+		NewDeathsAvg14d *float64 `json:"new_deaths_avg_14d"`
+	}
+
+	// TrendSummary describes the last day of the range. WeeklyGrowthRate is the
+	// percentage change of new cases over the last 7 days against the 7 before,
+	// DoublingTimeDays the days total cases take to double at last week's pace.
+	TrendSummary struct {
+		Date             string   `json:"date"`
+		WeeklyGrowthRate *float64 `json:"weekly_growth_rate"`
+		DoublingTimeDays *float64 `json:"doubling_time_days"`
+	}
+
+	CountryTrends struct {
+		Country models.CountryResponse `json:"country"`
+		From    string                 `json:"from"`
+		To      string                 `json:"to"`
+		Series  []TrendPoint           `json:"series"`
+		Summary TrendSummary           `json:"summary"`
+	}
+)
+
+const (
+	shortWindow = 7
+	longWindow  = 14
+)
+
+// Trends smooths the daily new cases and deaths of a country. Daily figures are
+// the day over day increase of the snapshot totals, so a day without snapshot,
+// or following one, is unknown and so is every average whose window covers it.
+func (s CoronaModule) Trends(ctx context.Context, param TrendsParam) (interface{}, *helpers.Error) {
+
+	if param.To.IsZero() {
+		param.To = time.Now().UTC()
+	}
+
+	if param.From.IsZero() {
+		param.From = param.To.AddDate(0, 0, -defaultTimelineDays)
+	}
+
+	param.From = truncateDay(param.From)
+	param.To = truncateDay(param.To)
+
+	if err := checkRange(param.From, param.To); err != nil {
+		return nil, helpers.ErrorWrap(err, s.name, "Trends/Range",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	country, err := s.store.Country.GetOneByName(ctx, param.Country)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helpers.ErrorWrap(err, s.name, "Trends/GetOneCountryByName",
+				helpers.NotFoundMessage, http.StatusNotFound)
+		}
+		return nil, helpers.ErrorWrap(err, s.name, "Trends/GetOneCountryByName",
+			helpers.InternalServerError, http.StatusInternalServerError)
+	}
+
+	// One extra day for the first increase and the windows reaching before from.
+	start := param.From.AddDate(0, 0, -longWindow)
+
+	snapshots, err := s.store.Corona.GetAllSnapshotByCountry(ctx, country.Country.Id, start, param.To)
+
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, s.name, "Trends/GetAllCoronaSnapshotByCountry",
+			helpers.InternalServerError, http.StatusInternalServerError)
+	}
+
+	trends := CountryTrends{
+		Country: country.Response(),
+		From:    param.From.Format(helpers.DateLayout),
+		To:      param.To.Format(helpers.DateLayout),
+	}
+
+	trends.Series, trends.Summary = computeTrends(snapshots, start, param.From, param.To)
+
+	return trends, nil
+
+}
+
+// computeTrends lays the snapshots out on one slot per day from start to to and
+// returns the points from from onwards along with the summary of the last day.
+func computeTrends(snapshots []models.CoronaSnapshotModel, start, from, to time.Time) ([]TrendPoint, TrendSummary) {
+
+	days := int(to.Sub(start).Hours()/24) + 1
+
+	totalCases := make([]float64, days)
+	totalDeaths := make([]float64, days)
+	for i := range totalCases {
+		totalCases[i] = math.NaN()
+		totalDeaths[i] = math.NaN()
+	}
+
+	for _, snapshot := range snapshots {
+		i := int(truncateDay(snapshot.ReportDate).Sub(start).Hours() / 24)
+		if i < 0 || i >= days {
+			continue
+		}
+		if snapshot.TotalCases.Valid {
+			totalCases[i] = float64(snapshot.TotalCases.Int64)
+		}
+		if snapshot.TotalDeaths.Valid {
+			totalDeaths[i] = float64(snapshot.TotalDeaths.Int64)
+		}
+	}
+
+	newCases := series.Floats(dailyIncrease(totalCases))
+	newDeaths := series.Floats(dailyIncrease(totalDeaths))
+
+	var points []TrendPoint
+	for i := int(from.Sub(start).Hours() / 24); i < days; i++ {
+		points = append(points, TrendPoint{
+			Date:            start.AddDate(0, 0, i).Format(helpers.DateLayout),
+			NewCases:        floatOrNull(newCases.Elem(i).Float()),
+			NewDeaths:       floatOrNull(newDeaths.Elem(i).Float()),
+			NewCasesAvg7d:   floatOrNull(rollingMean(newCases, i, shortWindow)),
+			NewCasesAvg14d:  floatOrNull(rollingMean(newCases, i, longWindow)),
+			NewDeathsAvg7d:  floatOrNull(rollingMean(newDeaths, i, shortWindow)),
+			NewDeathsAvg14d: floatOrNull(rollingMean(newDeaths, i, longWindow)),
+		})
+	}
+
+	last := days - 1
+	summary := TrendSummary{
+		Date: to.Format(helpers.DateLayout),
+	}
+
+	thisWeek := rollingMean(newCases, last, shortWindow)
+	lastWeek := rollingMean(newCases, last-shortWindow, shortWindow)
+	if lastWeek > 0 {
+		summary.WeeklyGrowthRate = floatOrNull((thisWeek/lastWeek - 1) * 100)
+	}
+
+	if last >= shortWindow && totalCases[last-shortWindow] > 0 {
+		growth := totalCases[last] / totalCases[last-shortWindow]
+		if growth > 1 {
+			summary.DoublingTimeDays = floatOrNull(shortWindow * math.Ln2 / math.Log(growth))
+		}
+	}
+
+	return points, summary
+}
+
+// dailyIncrease turns cumulative totals into per day increases. The first day
+// and any day next to an unknown total are unknown.
+func dailyIncrease(totals []float64) []float64 {
+
+	increases := make([]float64, len(totals))
+	increases[0] = math.NaN()
+
+	for i := 1; i < len(totals); i++ {
+		increases[i] = totals[i] - totals[i-1]
+	}
+
+	return increases
+}
+
+// rollingMean is the mean of the window days ending at end, NaN when the window
+// does not fit or covers an unknown day.
+func rollingMean(values series.Series, end, window int) float64 {
+
+	if end-window+1 < 0 || end >= values.Len() {
+		return math.NaN()
+	}
+
+	indexes := make([]int, window)
+	for i := range indexes {
+		indexes[i] = end - window + 1 + i
+	}
+
+	return values.Subset(indexes).Mean()
+}
+
+func floatOrNull(value float64) *float64 {
+
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return nil
+	}
+
+	value = math.Round(value*100) / 100
+
+	return &value
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,141 @@
+package api
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"database/sql"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestComputeTrends(t *testing.T) {
+
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// Total cases grow by 10 a day for a week, then by 20 a day.
+	var snapshots []models.CoronaSnapshotModel
+	total := int64(1000)
+	for day := 0; day < 15; day++ {
+		if day > 0 && day <= 7 {
+			total += 10
+		} else if day > 7 {
+			total += 20
+		}
+		snapshots = append(snapshots, models.CoronaSnapshotModel{
+			ReportDate:  start.AddDate(0, 0, day),
+			TotalCases:  sql.NullInt64{Int64: total, Valid: true},
+			TotalDeaths: sql.NullInt64{Int64: 5, Valid: true},
+		})
+	}
+
+	from := start.AddDate(0, 0, 7)
+	to := start.AddDate(0, 0, 14)
+
+	points, summary := computeTrends(snapshots, start, from, to)
+
+	if len(points) != 8 {
+		t.Fatalf("got %d points, want 8", len(points))
+	}
+
+	first := points[0]
+	if first.Date != "2020-06-08" || first.NewCases == nil || *first.NewCases != 10 ||
+		first.NewCasesAvg7d == nil || *first.NewCasesAvg7d != 10 || first.NewCasesAvg14d != nil {
+		t.Errorf("unexpected first point: %+v", first)
+	}
+
+	last := points[len(points)-1]
+	if last.NewCasesAvg7d == nil || *last.NewCasesAvg7d != 20 ||
+		last.NewCasesAvg14d == nil || *last.NewCasesAvg14d != 15 ||
+		last.NewDeathsAvg7d == nil || *last.NewDeathsAvg7d != 0 {
+		t.Errorf("unexpected last point: %+v", last)
+	}
+
+	if summary.WeeklyGrowthRate == nil || *summary.WeeklyGrowthRate != 100 {
+		t.Errorf("weekly growth rate = %v, want 100", summary.WeeklyGrowthRate)
+	}
+
+	if summary.DoublingTimeDays == nil || *summary.DoublingTimeDays != 39.46 {
+		t.Errorf("doubling time = %v, want 39.46", summary.DoublingTimeDays)
+	}
+}
+
+func TestComputeTrendsGap(t *testing.T) {
+
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	snapshots := []models.CoronaSnapshotModel{
+		{ReportDate: start, TotalCases: sql.NullInt64{Int64: 10, Valid: true}},
+		{ReportDate: start.AddDate(0, 0, 2), TotalCases: sql.NullInt64{Int64: 30, Valid: true}},
+	}
+
+	points, summary := computeTrends(snapshots, start, start, start.AddDate(0, 0, 2))
+
+	for _, point := range points {
+		if point.NewCases != nil || point.NewCasesAvg7d != nil {
+			t.Errorf("expected unknown values around the gap, got %+v", point)
+		}
+	}
+
+	if summary.WeeklyGrowthRate != nil || summary.DoublingTimeDays != nil {
+		t.Errorf("expected an unknown summary, got %+v", summary)
+	}
+}
+
+func TestComputeTrendsFromZero(t *testing.T) {
+
+	start := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// No cases for the first week, then 10 new cases a day.
+	var snapshots []models.CoronaSnapshotModel
+	total := int64(0)
+	for day := 0; day < 15; day++ {
+		if day > 7 {
+			total += 10
+		}
+		snapshots = append(snapshots, models.CoronaSnapshotModel{
+			ReportDate: start.AddDate(0, 0, day),
+			TotalCases: sql.NullInt64{Int64: total, Valid: true},
+		})
+	}
+
+	_, summary := computeTrends(snapshots, start, start.AddDate(0, 0, 7), start.AddDate(0, 0, 14))
+
+	if summary.DoublingTimeDays != nil {
+		t.Errorf("doubling time = %v, want null", *summary.DoublingTimeDays)
+	}
+}
+
+func TestTrendsRange(t *testing.T) {
+
+	ctx := context.Background()
+	module := NewCoronaModule(newCoronaStore(t), nil, helpers.NewLogger())
+
+	to := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		from   time.Time
+		status int
+	}{
+		{to.AddDate(0, 0, -maxTimelineDays), http.StatusOK},
+		{to.AddDate(0, 0, -maxTimelineDays-1), http.StatusBadRequest},
+		{time.Date(2, 1, 1, 0, 0, 0, 0, time.UTC), http.StatusBadRequest},
+		{to.AddDate(0, 0, 1), http.StatusBadRequest},
+	}
+
+	for _, test := range tests {
+
+		_, cerr := module.Trends(ctx, TrendsParam{Country: "Germany", From: test.from, To: to})
+		if status := statusOf(cerr); status != test.status {
+			t.Errorf("Trends from %s = %d, want %d", test.from.Format(helpers.DateLayout), status, test.status)
+		}
+	}
+}
+
+func statusOf(err *helpers.Error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	return err.StatusCode
+}
@@ -0,0 +1,772 @@
+package api
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"corona/scrapper"
+	"corona/storage"
+	"database/sql"
+	"encoding/json"
+	uuid "github.com/satori/go.uuid"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newCoronaStore returns a store with the figures of a few countries.
+func newCoronaStore(t *testing.T) storage.Store {
+	t.Helper()
+
+	ctx := context.Background()
+	store := storage.NewMemory()
+
+	continents := make(map[string]models.ContinentModel)
+	for _, name := range []string{"Europe", "Asia"} {
+		continent := models.ContinentModel{Name: name}
+		if err := store.Continent.Insert(ctx, &continent); err != nil {
+			t.Fatal(err)
+		}
+		continents[name] = continent
+	}
+
+	for _, row := range []struct {
+		country    string
+		continent  string
+		cases      sql.NullInt64
+		population sql.NullInt64
+	}{
+		{"Germany", "Europe", sql.NullInt64{Int64: 8000, Valid: true}, sql.NullInt64{Int64: 80000000, Valid: true}},
+		{"Iceland", "Europe", sql.NullInt64{Int64: 1800, Valid: true}, sql.NullInt64{Int64: 360000, Valid: true}},
+		{"Japan", "Asia", sql.NullInt64{Int64: 9000, Valid: true}, sql.NullInt64{Int64: 125000000, Valid: true}},
+		{"Vatican", "Europe", sql.NullInt64{}, sql.NullInt64{Int64: 800, Valid: true}},
+	} {
+		country := models.CountryModel{ContinentId: continents[row.continent].Id, Name: row.country}
+		if err := store.Country.Insert(ctx, &country); err != nil {
+			t.Fatal(err)
+		}
+
+		data := models.CoronaModel{CountryId: country.Id, TotalCases: row.cases, Population: row.population}
+		if err := store.Corona.Insert(ctx, &data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return store
+}
+
+func TestCoronaTop(t *testing.T) {
+
+	module := NewCoronaModule(newCoronaStore(t), nil, helpers.NewLogger())
+
+	tests := []struct {
+		param TopParam
+		want  []string
+	}{
+		{TopParam{}, []string{"Japan", "Germany", "Iceland"}},
+		{TopParam{Metric: models.DerivedCasesPerMillion, Limit: 2}, []string{"Iceland", "Germany"}},
+		{TopParam{Continent: "europe"}, []string{"Germany", "Iceland"}},
+	}
+
+	for _, test := range tests {
+
+		result, err := module.Top(context.Background(), test.param)
+
+		if err != nil {
+			t.Fatalf("Top(%+v): %v", test.param, err.Err)
+		}
+
+		var got []string
+		for _, rank := range result.(CoronaTop).Ranking {
+			got = append(got, rank.Country.Name)
+		}
+
+		if len(got) != len(test.want) {
+			t.Errorf("Top(%+v) = %v, want %v", test.param, got, test.want)
+			continue
+		}
+
+		for i := range got {
+			if got[i] != test.want[i] {
+				t.Errorf("Top(%+v) = %v, want %v", test.param, got, test.want)
+				break
+			}
+		}
+	}
+
+	for _, param := range []TopParam{{Metric: models.SortName}, {Limit: maxTopLimit + 1}} {
+		if _, err := module.Top(context.Background(), param); err == nil || err.StatusCode != http.StatusBadRequest {
+			t.Errorf("Top(%+v) should be a bad request, got %v", param, err)
+		}
+	}
+}
+
+func TestCoronaListSort(t *testing.T) {
+
+	module := NewCoronaModule(newCoronaStore(t), nil, helpers.NewLogger())
+
+	filter := helpers.Filter{
+		FilterOption: helpers.FilterOption{Limit: 10, Sort: models.MetricTotalCases, Dir: "desc"},
+	}
+
+	result, err := module.List(context.Background(), filter)
+
+	if err != nil {
+		t.Fatal(err.Err)
+	}
+
+	var got []string
+	for _, data := range result.(helpers.Page).Data.([]models.CoronaResponse) {
+		got = append(got, data.Country.Name)
+	}
+
+	// Unknown figures come last whatever the direction.
+	want := []string{"Japan", "Germany", "Iceland", "Vatican"}
+	for i := range want {
+		if i >= len(got) || got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+
+	filter.Sort = "name; DROP TABLE country"
+	if _, err := module.List(context.Background(), filter); err == nil || err.StatusCode != http.StatusBadRequest {
+		t.Errorf("an unknown sort should be a bad request, got %v", err)
+	}
+}
+
+func TestCoronaListFilters(t *testing.T) {
+
+	module := NewCoronaModule(newCoronaStore(t), nil, helpers.NewLogger())
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"continent=europe", []string{"Germany", "Iceland", "Vatican"}},
+		{"total_cases_gte=2000", []string{"Germany", "Japan"}},
+		{"continent=Europe&total_cases_gte=1000&population_gte=1000000", []string{"Germany"}},
+		{"cases_per_million_gte=1000", []string{"Iceland"}},
+		{"updated_since=2000-01-01", []string{"Germany", "Iceland", "Japan", "Vatican"}},
+		{"updated_since=2999-01-01T00:00:00Z", nil},
+	}
+
+	for _, test := range tests {
+
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/coronavirus?limit=10&"+test.query, nil)
+
+		filter, err := helpers.ParseFilter(r.Context(), r)
+
+		if err != nil {
+			t.Fatalf("%s: %v", test.query, err)
+		}
+
+		result, errList := module.List(r.Context(), filter)
+
+		if errList != nil {
+			t.Fatalf("%s: %v", test.query, errList.Err)
+		}
+
+		var got []string
+		for _, data := range result.(helpers.Page).Data.([]models.CoronaResponse) {
+			got = append(got, data.Country.Name)
+		}
+
+		if strings.Join(got, ",") != strings.Join(test.want, ",") {
+			t.Errorf("%s: got %v, want %v", test.query, got, test.want)
+		}
+	}
+
+	for _, query := range []string{"total_cases_gte=many", "updated_since=yesterday", "limit=ten"} {
+
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/coronavirus?"+query, nil)
+
+		if _, err := helpers.ParseFilter(r.Context(), r); err == nil {
+			t.Errorf("%s: expected an error", query)
+		}
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/coronavirus?limit=10&name_gte=1", nil)
+	filter, _ := helpers.ParseFilter(r.Context(), r)
+
+	if _, err := module.List(r.Context(), filter); err == nil || err.StatusCode != http.StatusBadRequest {
+		t.Errorf("a bound on an unknown field should be a bad request, got %v", err)
+	}
+}
+
+func TestCoronaListPages(t *testing.T) {
+
+	module := NewCoronaModule(newCoronaStore(t), nil, helpers.NewLogger())
+
+	// list follows target, returning the names on the page and its meta.
+	list := func(target string) ([]string, *helpers.Meta) {
+		t.Helper()
+
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+
+		filter, err := helpers.ParseFilter(r.Context(), r)
+
+		if err != nil {
+			t.Fatalf("%s: %v", target, err)
+		}
+
+		result, errList := module.List(r.Context(), filter)
+
+		if errList != nil {
+			t.Fatalf("%s: %v", target, errList.Err)
+		}
+
+		page := result.(helpers.Page)
+
+		var names []string
+		for _, data := range page.Data.([]models.CoronaResponse) {
+			names = append(names, data.Country.Name)
+		}
+
+		return names, page.Meta(r.URL)
+	}
+
+	names, meta := list("/api/v1/coronavirus")
+	if len(names) != 4 || meta.Limit != helpers.DefaultLimit || meta.Total != 4 || meta.Next != "" {
+		t.Errorf("without a limit got %v and %+v", names, meta)
+	}
+
+	names, meta = list("/api/v1/coronavirus?limit=1000")
+	if meta.Limit != helpers.MaxLimit {
+		t.Errorf("got limit %d, want it capped at %d", meta.Limit, helpers.MaxLimit)
+	}
+
+	// Offsets link both ways.
+	names, meta = list("/api/v1/coronavirus?sort=total_cases&dir=desc&limit=2")
+	if strings.Join(names, ",") != "Japan,Germany" || meta.Total != 4 || meta.Prev != "" {
+		t.Fatalf("got %v and %+v", names, meta)
+	}
+
+	names, meta = list(meta.Next)
+	if strings.Join(names, ",") != "Iceland,Vatican" || *meta.Offset != 2 || meta.Next != "" {
+		t.Fatalf("got %v and %+v", names, meta)
+	}
+
+	if names, _ = list(meta.Prev); strings.Join(names, ",") != "Japan,Germany" {
+		t.Errorf("previous page got %v", names)
+	}
+
+	// Cursors seek past the last row, unknown values last.
+	var got []string
+	next := "/api/v1/coronavirus?sort=total_cases&dir=desc&limit=1&cursor=" + firstCursor(t, module)
+	for next != "" {
+		names, meta = list(next)
+		got = append(got, names...)
+		next = meta.Next
+	}
+
+	if strings.Join(got, ",") != "Germany,Iceland,Vatican" {
+		t.Errorf("paging with cursors got %v", got)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/coronavirus?sort=name&cursor="+firstCursor(t, module), nil)
+	filter, _ := helpers.ParseFilter(r.Context(), r)
+
+	if _, err := module.List(r.Context(), filter); err == nil || err.StatusCode != http.StatusBadRequest {
+		t.Errorf("a cursor of another sort should be a bad request, got %v", err)
+	}
+
+	r = httptest.NewRequest(http.MethodGet, "/api/v1/coronavirus?cursor=not-a-cursor", nil)
+	if _, err := helpers.ParseFilter(r.Context(), r); err == nil {
+		t.Error("expected an error for a malformed cursor")
+	}
+}
+
+// TestCoronaListCursorTies pages through two countries of the same name and
+// figures, which only their id tells apart.
+func TestCoronaListCursorTies(t *testing.T) {
+
+	ctx := context.Background()
+	store := newCoronaStore(t)
+
+	germany, err := store.Country.GetOneByName(ctx, "Germany")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	twin := models.CountryModel{ContinentId: germany.Country.ContinentId, Name: "Germany"}
+	if err := store.Country.Insert(ctx, &twin); err != nil {
+		t.Fatal(err)
+	}
+
+	data := models.CoronaModel{CountryId: twin.Id, TotalCases: sql.NullInt64{Int64: 8000, Valid: true}}
+	if err := store.Corona.Insert(ctx, &data); err != nil {
+		t.Fatal(err)
+	}
+
+	module := NewCoronaModule(store, nil, helpers.NewLogger())
+
+	for _, sort := range []string{"", models.SortName, models.MetricTotalCases} {
+
+		filter := helpers.Filter{FilterOption: helpers.FilterOption{Limit: 1, Sort: sort, Dir: "desc"}}
+
+		var ids []string
+		for {
+			result, cerr := module.List(ctx, filter)
+			if cerr != nil {
+				t.Fatal(cerr.Err)
+			}
+
+			page := result.(helpers.Page)
+			for _, data := range page.Data.([]models.CoronaResponse) {
+				ids = append(ids, data.Country.Id.String())
+			}
+
+			if page.Cursor == "" {
+				break
+			}
+
+			if filter.Cursor, err = helpers.DecodeCursor(page.Cursor); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		seen := make(map[string]bool)
+		for _, id := range ids {
+			seen[id] = true
+		}
+
+		if len(ids) != 5 || len(seen) != 5 {
+			t.Errorf("sort %q paged through %v", sort, ids)
+		}
+	}
+}
+
+func TestCoronaListCursorRounded(t *testing.T) {
+
+	ctx := context.Background()
+	store := storage.NewMemory()
+
+	continent := models.ContinentModel{Name: "Europe"}
+	if err := store.Continent.Insert(ctx, &continent); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both show 12.34 cases per million, Malta is 12.341 before rounding.
+	for _, row := range []struct {
+		country    string
+		cases      int64
+		population int64
+	}{
+		{"Andorra", 1234, 100000000},
+		{"Malta", 12341, 1000000000},
+	} {
+		country := models.CountryModel{ContinentId: continent.Id, Name: row.country}
+		if err := store.Country.Insert(ctx, &country); err != nil {
+			t.Fatal(err)
+		}
+
+		data := models.CoronaModel{
+			CountryId:  country.Id,
+			TotalCases: sql.NullInt64{Int64: row.cases, Valid: true},
+			Population: sql.NullInt64{Int64: row.population, Valid: true},
+		}
+		if err := store.Corona.Insert(ctx, &data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	module := NewCoronaModule(store, nil, helpers.NewLogger())
+
+	filter := helpers.Filter{FilterOption: helpers.FilterOption{Limit: 1, Sort: models.DerivedCasesPerMillion, Dir: "desc"}}
+
+	var names []string
+	for {
+		result, cerr := module.List(ctx, filter)
+		if cerr != nil {
+			t.Fatal(cerr.Err)
+		}
+
+		page := result.(helpers.Page)
+		for _, data := range page.Data.([]models.CoronaResponse) {
+			names = append(names, data.Country.Name)
+		}
+
+		if page.Cursor == "" {
+			break
+		}
+
+		cursor, err := helpers.DecodeCursor(page.Cursor)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cursor.Value == nil || *cursor.Value != "12.34" {
+			t.Fatalf("cursor value = %v, want the shown 12.34", cursor.Value)
+		}
+		filter.Cursor = cursor
+	}
+
+	if len(names) != 2 || names[0] == names[1] {
+		t.Errorf("paged through %v, want both countries once", names)
+	}
+}
+
+// firstCursor returns the cursor after the first country by total cases.
+func firstCursor(t *testing.T, module *CoronaModule) string {
+	t.Helper()
+
+	filter := helpers.Filter{
+		FilterOption: helpers.FilterOption{Limit: 1, Sort: models.MetricTotalCases, Dir: "DESC"},
+	}
+
+	result, err := module.List(context.Background(), filter)
+
+	if err != nil {
+		t.Fatal(err.Err)
+	}
+
+	return result.(helpers.Page).Cursor
+}
+
+func TestCoronaTimeline(t *testing.T) {
+
+	ctx := context.Background()
+	store := newCoronaStore(t)
+
+	germany, err := store.Country.GetOneByName(ctx, "Germany")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+	for i, cases := range []sql.NullInt64{{Int64: 100, Valid: true}, {}} {
+		snapshot := models.CoronaSnapshotModel{
+			CountryId:  germany.Country.Id,
+			ReportDate: day.AddDate(0, 0, i),
+			TotalCases: cases,
+		}
+		if err := store.Corona.UpsertSnapshot(ctx, &snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	module := NewCoronaModule(store, nil, helpers.NewLogger())
+
+	result, cerr := module.Timeline(ctx, TimelineParam{
+		Country: "germany",
+		From:    day,
+		To:      day.AddDate(0, 0, 7),
+		Metrics: []string{models.MetricTotalCases},
+	})
+	if cerr != nil {
+		t.Fatal(cerr.Err)
+	}
+
+	points := result.(CountryTimeline).Series[models.MetricTotalCases]
+	if len(points) != 2 || points[0].Date != "2020-04-01" || points[0].Value == nil || *points[0].Value != 100 ||
+		points[1].Date != "2020-04-02" || points[1].Value != nil {
+		t.Errorf("got points %+v", points)
+	}
+
+	for _, test := range []struct {
+		param  TimelineParam
+		status int
+	}{
+		{TimelineParam{Country: "Germany", From: day.AddDate(0, 0, -maxTimelineDays), To: day}, http.StatusOK},
+		{TimelineParam{Country: "Germany", From: day.AddDate(0, 0, -maxTimelineDays-1), To: day}, http.StatusBadRequest},
+		{TimelineParam{Country: "Germany", From: day.AddDate(0, 0, 1), To: day}, http.StatusBadRequest},
+		{TimelineParam{Country: "Germany", Metrics: []string{"cases_per_million"}}, http.StatusBadRequest},
+		{TimelineParam{Country: "Atlantis"}, http.StatusNotFound},
+	} {
+		if _, cerr := module.Timeline(ctx, test.param); statusOf(cerr) != test.status {
+			t.Errorf("Timeline(%+v) = %v, want status %d", test.param, cerr, test.status)
+		}
+	}
+}
+
+func TestCoronaByContinent(t *testing.T) {
+
+	ctx := context.Background()
+	module := NewCoronaModule(newCoronaStore(t), nil, helpers.NewLogger())
+
+	filter := helpers.Filter{FilterOption: helpers.FilterOption{Limit: 2}}
+
+	result, cerr := module.ByContinent(ctx, filter, ByContinentParam{Continent: "Europe"})
+	if cerr != nil {
+		t.Fatal(cerr.Err)
+	}
+
+	page := result.(helpers.Page)
+	continent := page.Data.(ContinentCorona)
+
+	// Totals cover every country of the continent, whatever the page.
+	totals := continent.Totals
+	if totals.Continent.Name != "Europe" || totals.ReportingCountries != 3 || *totals.TotalCases != 9800 ||
+		*totals.Population != 80360800 || totals.TotalDeaths != nil {
+		t.Errorf("got totals %+v", totals)
+	}
+
+	var names []string
+	for _, data := range continent.Countries {
+		names = append(names, data.Country.Name)
+	}
+	if strings.Join(names, ",") != "Germany,Iceland" || page.Total != 3 {
+		t.Errorf("got countries %v of %d", names, page.Total)
+	}
+
+	body, err := json.Marshal(page.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var shape map[string]json.RawMessage
+	if err := json.Unmarshal(body, &shape); err != nil || len(shape) != 2 || shape["totals"] == nil ||
+		shape["countries"] == nil {
+		t.Errorf("got %s, want an object of totals and countries", body)
+	}
+}
+
+func TestCoronaContinents(t *testing.T) {
+
+	module := NewCoronaModule(newCoronaStore(t), nil, helpers.NewLogger())
+
+	result, cerr := module.Continents(context.Background())
+	if cerr != nil {
+		t.Fatal(cerr.Err)
+	}
+
+	totals := result.([]models.CoronaTotalResponse)
+	if len(totals) != 2 || totals[0].Continent.Name != "Asia" || *totals[0].TotalCases != 9000 ||
+		totals[0].ReportingCountries != 1 || totals[1].Continent.Name != "Europe" || *totals[1].TotalCases != 9800 {
+		t.Errorf("got totals %+v", totals)
+	}
+}
+
+func TestCoronaCompare(t *testing.T) {
+
+	ctx := context.Background()
+	store := newCoronaStore(t)
+
+	germany, err := store.Country.GetOneByName(ctx, "Germany")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	france := models.CountryModel{ContinentId: germany.Country.ContinentId, Name: "France", Code: "FR", Alpha3: "FRA"}
+	if err := store.Country.Insert(ctx, &france); err != nil {
+		t.Fatal(err)
+	}
+
+	day := time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)
+	for _, snapshot := range []models.CoronaSnapshotModel{
+		{CountryId: france.Id, ReportDate: day, TotalCases: sql.NullInt64{Int64: 100, Valid: true}},
+		{CountryId: france.Id, ReportDate: day.AddDate(0, 0, 1), TotalCases: sql.NullInt64{Int64: 150, Valid: true}},
+		{CountryId: germany.Country.Id, ReportDate: day.AddDate(0, 0, 1), TotalCases: sql.NullInt64{Int64: 80, Valid: true}},
+	} {
+		snapshot := snapshot
+		if err := store.Corona.UpsertSnapshot(ctx, &snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	module := NewCoronaModule(store, nil, helpers.NewLogger())
+
+	result, cerr := module.Compare(ctx, CompareParam{
+		Countries: []string{"fra", "Germany", "Japan", "FR", "France"},
+		Metrics:   []string{models.MetricTotalCases, models.DerivedCasesPerMillion},
+		From:      day,
+		To:        day.AddDate(0, 0, 7),
+	})
+	if cerr != nil {
+		t.Fatal(cerr.Err)
+	}
+
+	comparison := result.(CoronaComparison)
+
+	var names []string
+	for _, country := range comparison.Countries {
+		names = append(names, country.Name)
+	}
+	if strings.Join(names, ",") != "France,Germany,Japan" {
+		t.Fatalf("countries = %v", names)
+	}
+
+	values := func(points []*float64) string {
+		var out []string
+		for _, point := range points {
+			if point == nil {
+				out = append(out, "null")
+				continue
+			}
+			out = append(out, strconv.FormatFloat(*point, 'f', -1, 64))
+		}
+		return strings.Join(out, ",")
+	}
+
+	if got := values(comparison.Values[models.MetricTotalCases]); got != "null,8000,9000" {
+		t.Errorf("total_cases = %s", got)
+	}
+	if got := values(comparison.Values[models.DerivedCasesPerMillion]); got != "null,100,72" {
+		t.Errorf("cases_per_million = %s", got)
+	}
+
+	if comparison.Timeline == nil {
+		t.Fatal("no timeline")
+	}
+	if got := strings.Join(comparison.Timeline.Dates, ","); got != "2020-04-01,2020-04-02" {
+		t.Errorf("dates = %s", got)
+	}
+
+	series := comparison.Timeline.Series[models.MetricTotalCases]
+	for i, want := range []string{"100,150", "null,80", "null,null"} {
+		if got := values(series[i]); got != want {
+			t.Errorf("total_cases of %s = %s, want %s", names[i], got, want)
+		}
+	}
+
+	result, cerr = module.Compare(ctx, CompareParam{Countries: []string{"Iceland"}})
+	if cerr != nil {
+		t.Fatal(cerr.Err)
+	}
+	if comparison := result.(CoronaComparison); comparison.Timeline != nil ||
+		len(comparison.Values) != len(models.CoronaMetrics)+len(models.DerivedMetrics) {
+		t.Errorf("comparison without snapshots = %+v", comparison)
+	}
+
+	for _, test := range []struct {
+		param  CompareParam
+		status int
+	}{
+		{CompareParam{}, http.StatusBadRequest},
+		{CompareParam{Countries: []string{"Germany"}, Metrics: []string{"name"}}, http.StatusBadRequest},
+		{CompareParam{Countries: []string{"Germany", "Atlantis"}}, http.StatusNotFound},
+		{CompareParam{Countries: []string{"XX"}}, http.StatusNotFound},
+		{CompareParam{Countries: []string{"Germany"}, From: day.AddDate(-2, 0, 0), To: day}, http.StatusBadRequest},
+	} {
+		if _, cerr := module.Compare(ctx, test.param); cerr == nil || cerr.StatusCode != test.status {
+			t.Errorf("Compare(%+v) = %v, want status %d", test.param, cerr, test.status)
+		}
+	}
+}
+
+func TestCoronaAdd(t *testing.T) {
+
+	ctx := context.Background()
+	store := storage.NewMemory()
+
+	continent := models.ContinentModel{Name: "America"}
+	if err := store.Continent.Insert(ctx, &continent); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"USA", "Brazil"} {
+		country := models.CountryModel{ContinentId: continent.Id, Name: name}
+		if err := store.Country.Insert(ctx, &country); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "../scrapper/testdata/worldometers.html")
+	}))
+	defer server.Close()
+
+	s, err := scrapper.NewScrapper(store, helpers.NewLogger(), scrapper.Option{
+		SourceOption: map[string]interface{}{"url": server.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	module := NewCoronaModule(store, s, helpers.NewLogger())
+
+	for _, want := range []models.UpsertSummary{{Inserted: 2}, {Unchanged: 2}} {
+
+		result, cerr := module.Add(ctx)
+		if cerr != nil {
+			t.Fatal(cerr.Err)
+		}
+
+		if summary := result.(models.UpsertSummary); summary != want {
+			t.Errorf("Add() = %+v, want %+v", summary, want)
+		}
+	}
+
+	total, err := store.Corona.Count(ctx, helpers.Filter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Errorf("%d corona rows, want 2", total)
+	}
+}
+
+// extraCountries adds countries the store does not hold to the ones a scrape
+// is matched against.
+type extraCountries struct {
+	storage.CountryRepository
+	extra []models.CountryModel
+}
+
+func (c extraCountries) All(ctx context.Context) ([]models.CountryModel, error) {
+
+	countries, err := c.CountryRepository.All(ctx)
+
+	return append(countries, c.extra...), err
+}
+
+func TestCoronaUpdateAllRollback(t *testing.T) {
+
+	ctx := context.Background()
+	store := storage.NewMemory()
+
+	continent := models.ContinentModel{Name: "America"}
+	if err := store.Continent.Insert(ctx, &continent); err != nil {
+		t.Fatal(err)
+	}
+
+	usa := models.CountryModel{ContinentId: continent.Id, Name: "USA"}
+	if err := store.Country.Insert(ctx, &usa); err != nil {
+		t.Fatal(err)
+	}
+
+	earlier := models.CoronaModel{CountryId: usa.Id, TotalCases: sql.NullInt64{Int64: 1, Valid: true}}
+	if err := store.Corona.Insert(ctx, &earlier); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "../scrapper/testdata/worldometers.html")
+	}))
+	defer server.Close()
+
+	// Brazil is matched by the scrape but missing from the store, so storing it
+	// fails after USA has been updated.
+	scrapeStore := store
+	scrapeStore.Country = extraCountries{
+		CountryRepository: store.Country,
+		extra:             []models.CountryModel{{Id: uuid.NewV4(), ContinentId: continent.Id, Name: "Brazil"}},
+	}
+
+	s, err := scrapper.NewScrapper(scrapeStore, helpers.NewLogger(), scrapper.Option{
+		SourceOption: map[string]interface{}{"url": server.URL},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	module := NewCoronaModule(store, s, helpers.NewLogger())
+
+	if _, err := module.UpdateAll(ctx); err == nil {
+		t.Fatal("expected UpdateAll to fail")
+	}
+
+	detail, err := store.Corona.GetByCountry(ctx, "usa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if detail.Corona.TotalCases != earlier.TotalCases {
+		t.Errorf("USA total cases = %v, want the earlier %v", detail.Corona.TotalCases, earlier.TotalCases)
+	}
+
+	if _, err := store.Corona.GetLatestWorldSummary(ctx); err != sql.ErrNoRows {
+		t.Errorf("expected no world summary after the rollback, got %v", err)
+	}
+}
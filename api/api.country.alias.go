@@ -0,0 +1,124 @@
+package api
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"corona/storage"
+	"database/sql"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"html"
+	"net/http"
+	"strings"
+)
+
+type (
+	CountryAliasModule struct {
+		store  storage.Store
+		logger *helpers.Logger
+		name   string
+	}
+
+	CountryAliasListParam struct {
+		CountryId uuid.UUID `json:"country_id"`
+	}
+
+	CountryAliasAddParam struct {
+		CountryId uuid.UUID `json:"-"`
+		Alias     string    `json:"alias" validate:"required"`
+		Source    string    `json:"source"`
+	}
+
+	CountryAliasDeleteParam struct {
+		CountryId uuid.UUID `json:"country_id"`
+		Id        uuid.UUID `json:"id"`
+	}
+)
+
+func NewCountryAliasModule(store storage.Store, logger *helpers.Logger) *CountryAliasModule {
+	return &CountryAliasModule{
+		store:  store,
+		logger: logger,
+		name:   "module/country.alias",
+	}
+}
+
+func (s CountryAliasModule) List(ctx context.Context, param CountryAliasListParam) (interface{}, *helpers.Error) {
+
+	aliases, err := s.store.Country.GetAllAliasByCountry(ctx, param.CountryId)
+
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, s.name, "List/GetAllCountryAliasByCountry",
+			helpers.InternalServerError, http.StatusInternalServerError)
+	}
+
+	var aliasResponses []models.CountryAliasResponse
+	for _, alias := range aliases {
+		aliasResponses = append(aliasResponses, alias.Response())
+	}
+
+	return aliasResponses, nil
+}
+
+func (s CountryAliasModule) Add(ctx context.Context, param CountryAliasAddParam) (interface{}, *helpers.Error) {
+
+	_, err := s.store.Country.GetOne(ctx, param.CountryId)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helpers.ErrorWrap(err, s.name, "Add/GetOneCountry", helpers.NotFoundMessage,
+				http.StatusNotFound)
+		}
+		return nil, helpers.ErrorWrap(err, s.name, "Add/GetOneCountry", helpers.InternalServerError,
+			http.StatusInternalServerError)
+	}
+
+	// ParseBodyRequestData html-escapes strings, but aliases are matched against the raw scraped text.
+	alias := models.CountryAliasModel{
+		CountryId: param.CountryId,
+		Alias:     strings.TrimSpace(html.UnescapeString(param.Alias)),
+		Source:    strings.TrimSpace(html.UnescapeString(param.Source)),
+		CreatedBy: uuid.NewV4(),
+	}
+
+	err = s.store.Country.InsertAlias(ctx, &alias)
+	if err != nil {
+		if errors.Cause(err) == helpers.ErrDuplicate {
+			return nil, helpers.ErrorWrap(err, s.name, "Add/Insert", helpers.AliasExistsMessage,
+				http.StatusConflict)
+		}
+		return nil, helpers.ErrorWrap(err, s.name, "Add/Insert", helpers.InternalServerError,
+			http.StatusInternalServerError)
+	}
+
+	return alias.Response(), nil
+
+}
+
+func (s CountryAliasModule) Delete(ctx context.Context, param CountryAliasDeleteParam) (interface{}, *helpers.Error) {
+
+	alias, err := s.store.Country.GetOneAlias(ctx, param.Id)
+
+	if err == nil && alias.CountryId != param.CountryId {
+		err = errors.Wrapf(sql.ErrNoRows, "alias %s does not belong to country %s", param.Id, param.CountryId)
+	}
+
+	if err != nil {
+		if errors.Cause(err) == sql.ErrNoRows {
+			return nil, helpers.ErrorWrap(err, s.name, "Delete/GetOneCountryAlias", helpers.NotFoundMessage,
+				http.StatusNotFound)
+		}
+		return nil, helpers.ErrorWrap(err, s.name, "Delete/GetOneCountryAlias", helpers.InternalServerError,
+			http.StatusInternalServerError)
+	}
+
+	err = s.store.Country.DeleteAlias(ctx, &alias)
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, s.name, "Delete/Delete", helpers.InternalServerError,
+			http.StatusInternalServerError)
+	}
+
+	return alias.Response(), nil
+
+}
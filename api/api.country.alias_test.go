@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"corona/storage"
+	"net/http"
+	"testing"
+)
+
+func TestCountryAliasAddDuplicate(t *testing.T) {
+
+	ctx := context.Background()
+	store := storage.NewMemory()
+
+	continent := models.ContinentModel{Name: "Asia"}
+	if err := store.Continent.Insert(ctx, &continent); err != nil {
+		t.Fatal(err)
+	}
+
+	country := models.CountryModel{ContinentId: continent.Id, Name: "South Korea"}
+	if err := store.Country.Insert(ctx, &country); err != nil {
+		t.Fatal(err)
+	}
+
+	module := NewCountryAliasModule(store, helpers.NewLogger())
+
+	param := CountryAliasAddParam{CountryId: country.Id, Alias: "S. Korea", Source: "worldometers"}
+	if _, err := module.Add(ctx, param); err != nil {
+		t.Fatal(err.Err)
+	}
+
+	param.Alias = "s. korea"
+	_, err := module.Add(ctx, param)
+	if err == nil || err.StatusCode != http.StatusConflict || err.Message != helpers.AliasExistsMessage {
+		t.Errorf("adding an alias twice got %v, want a conflict", err)
+	}
+
+	param.Source = "other"
+	if _, err := module.Add(ctx, param); err != nil {
+		t.Errorf("the same alias for another source got %v", err.Err)
+	}
+}
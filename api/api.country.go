@@ -4,14 +4,14 @@ import (
 	"context"
 	"corona/helpers"
 	"corona/models"
-	"database/sql"
+	"corona/storage"
 	uuid "github.com/satori/go.uuid"
 	"net/http"
 )
 
 type (
 	CountryModule struct {
-		db     *sql.DB
+		store  storage.Store
 		logger *helpers.Logger
 		name   string
 	}
@@ -24,6 +24,8 @@ type (
 		ContinentName string `json:"continent_name"`
 		Name          string `json:"name"`
 		Code          string `json:"code"`
+		Alpha3        string `json:"alpha3"`
+		NumericCode   string `json:"numeric_code"`
 	}
 
 	CountryAddParam struct {
@@ -31,9 +33,9 @@ type (
 	}
 )
 
-func NewCountryModule(db *sql.DB, logger *helpers.Logger) *CountryModule {
+func NewCountryModule(store storage.Store, logger *helpers.Logger) *CountryModule {
 	return &CountryModule{
-		db:     db,
+		store:  store,
 		logger: logger,
 		name:   "module/country",
 	}
@@ -41,48 +43,43 @@ func NewCountryModule(db *sql.DB, logger *helpers.Logger) *CountryModule {
 
 func (s CountryModule) Detail(ctx context.Context, param CountryDetailParam) (interface{}, *helpers.Error) {
 
-	country, err := models.GetOneCountry(ctx, s.db, param.Id)
+	country, err := s.store.Country.GetOne(ctx, param.Id)
 
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, s.name, "Detail/GetOneCountry", helpers.InternalServerError,
 			http.StatusInternalServerError)
 	}
 
-	response, err := country.Response(ctx, s.db, s.logger)
-
-	if err != nil {
-		return nil, helpers.ErrorWrap(err, s.name, "Detail/country.response", helpers.InternalServerError,
-			http.StatusInternalServerError)
-	}
-
-	return response, nil
+	return country.Response(), nil
 
 }
 
 func (s CountryModule) List(ctx context.Context, filter helpers.Filter) (interface{}, *helpers.Error) {
 
-	countries, err := models.GetAllCountries(ctx, s.db, filter)
+	countries, err := s.store.Country.GetAll(ctx, filter)
 
 	if err != nil {
-		return nil, helpers.ErrorWrap(err, s.name, "List/GetAllCountry", helpers.InternalServerError,
-			http.StatusInternalServerError)
+		return nil, helpers.FilterErrorWrap(err, s.name, "List/GetAllCountry")
 	}
 
-	var countryResponses []models.CountryResponse
+	total, err := s.store.Country.Count(ctx, filter)
 
-	for _, country := range countries {
+	if err != nil {
+		return nil, helpers.FilterErrorWrap(err, s.name, "List/CountCountry")
+	}
 
-		response, err := country.Response(ctx, s.db, s.logger)
-		if err != nil {
-			return nil, helpers.ErrorWrap(err, s.name, "List/country.Response", helpers.InternalServerError,
-				http.StatusInternalServerError)
-		}
+	var countryResponses []models.CountryResponse
 
-		countryResponses = append(countryResponses, response)
+	for _, country := range countries {
+		countryResponses = append(countryResponses, country.Response())
+	}
 
+	page := helpers.NewPage(countryResponses, filter, len(countries), total)
+	if page.More() {
+		page.Cursor = countries[len(countries)-1].Cursor(filter)
 	}
 
-	return countryResponses, nil
+	return page, nil
 }
 
 func (s CountryModule) Add(ctx context.Context, param CountryAddParam) (interface{}, *helpers.Error) {
@@ -91,7 +88,7 @@ func (s CountryModule) Add(ctx context.Context, param CountryAddParam) (interfac
 
 	for _, country := range param.Country {
 
-		continent, err := models.GetOneContinentByName(ctx, s.db, country.ContinentName)
+		continent, err := s.store.Continent.GetOneByName(ctx, country.ContinentName)
 
 		if err != nil {
 			return nil, helpers.ErrorWrap(err, s.name, "Add/GetOneContinentByName", helpers.InternalServerError,
@@ -102,23 +99,25 @@ func (s CountryModule) Add(ctx context.Context, param CountryAddParam) (interfac
 			ContinentId: continent.Id,
 			Name:        country.Name,
 			Code:        country.Code,
+			Alpha3:      country.Alpha3,
+			NumericCode: country.NumericCode,
 			CreatedBy:   uuid.NewV4(),
 		}
 
-		err = country.Insert(ctx, s.db)
+		err = s.store.Country.Insert(ctx, &country)
 		if err != nil {
 			return nil, helpers.ErrorWrap(err, s.name, "Add/Insert", helpers.InternalServerError,
 				http.StatusInternalServerError)
 		}
 
-		countryResponse, err := country.Response(ctx, s.db, s.logger)
+		detail, err := s.store.Country.GetOne(ctx, country.Id)
 
 		if err != nil {
-			return nil, helpers.ErrorWrap(err, s.name, "Add/country.Response", helpers.InternalServerError,
+			return nil, helpers.ErrorWrap(err, s.name, "Add/GetOneCountry", helpers.InternalServerError,
 				http.StatusInternalServerError)
 		}
 
-		countryResponses = append(countryResponses, countryResponse)
+		countryResponses = append(countryResponses, detail.Response())
 
 	}
 
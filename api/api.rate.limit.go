@@ -4,14 +4,14 @@ import (
 	"context"
 	"corona/helpers"
 	"corona/models"
-	"database/sql"
+	"corona/storage"
 	uuid "github.com/satori/go.uuid"
 	"net/http"
 )
 
 type (
 	RateLimitModule struct {
-		db     *sql.DB
+		store  storage.Store
 		logger *helpers.Logger
 		name   string
 	}
@@ -26,9 +26,9 @@ type (
 	}
 )
 
-func NewRateLimitModule(db *sql.DB, logger *helpers.Logger) *RateLimitModule {
+func NewRateLimitModule(store storage.Store, logger *helpers.Logger) *RateLimitModule {
 	return &RateLimitModule{
-		db:     db,
+		store:  store,
 		logger: logger,
 		name:   "module/rate.limit",
 	}
@@ -36,27 +36,20 @@ func NewRateLimitModule(db *sql.DB, logger *helpers.Logger) *RateLimitModule {
 
 func (r RateLimitModule) Detail(ctx context.Context, param RateLimitDetailParam) (interface{}, *helpers.Error) {
 
-	limit, err := models.GetOneRateLimit(ctx, r.db, param.Id)
+	limit, err := r.store.RateLimit.GetOne(ctx, param.Id)
 
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, r.name, "Detail/GetOneRateLimit", helpers.InternalServerError,
 			http.StatusInternalServerError)
 	}
 
-	response, err := limit.Response(ctx, r.db, r.logger)
-
-	if err != nil {
-		return nil, helpers.ErrorWrap(err, r.name, "Detail/limit.Response", helpers.InternalServerError,
-			http.StatusInternalServerError)
-	}
-
-	return response, nil
+	return limit.Response(), nil
 
 }
 
 func (r RateLimitModule) List(ctx context.Context) (interface{}, *helpers.Error) {
 
-	limits, err := models.GetAllRateLimit(ctx, r.db)
+	limits, err := r.store.RateLimit.GetAll(ctx)
 
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, r.name, "List/GetAllRateLimit", helpers.InternalServerError,
@@ -66,15 +59,7 @@ func (r RateLimitModule) List(ctx context.Context) (interface{}, *helpers.Error)
 	var limitResponse []models.RateLimitResponse
 
 	for _, limit := range limits {
-
-		response, err := limit.Response(ctx, r.db, r.logger)
-		if err != nil {
-			return nil, helpers.ErrorWrap(err, r.name, "List/limit.Response", helpers.InternalServerError,
-				http.StatusInternalServerError)
-		}
-
-		limitResponse = append(limitResponse, response)
-
+		limitResponse = append(limitResponse, limit.Response())
 	}
 
 	return limitResponse, nil
@@ -88,33 +73,37 @@ func (r RateLimitModule) Add(ctx context.Context, param RateLimitAddParam) (inte
 		CreatedBy:    uuid.NewV4(),
 	}
 
-	err := limit.Insert(ctx, r.db)
+	err := r.store.RateLimit.Insert(ctx, &limit)
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, r.name, "Add/Insert", helpers.InternalServerError,
 			http.StatusInternalServerError)
 	}
 
-	response, err := limit.Response(ctx, r.db, r.logger)
+	user, err := r.store.User.GetOne(ctx, limit.UserId)
 
 	if err != nil {
-		return nil, helpers.ErrorWrap(err, r.name, "Add/Response", helpers.InternalServerError,
+		return nil, helpers.ErrorWrap(err, r.name, "Add/GetOneUser", helpers.InternalServerError,
 			http.StatusInternalServerError)
 	}
 
-	return response, nil
+	detail := models.RateLimitDetailModel{RateLimit: limit, User: user}
+
+	return detail.Response(), nil
 
 }
 
-func UpdateRateLimit(ctx context.Context) error {
+// UpdateAll resets the request count of every user.
+func (r RateLimitModule) UpdateAll(ctx context.Context) error {
 
-	limits, err := models.GetAllRateLimit(ctx, dbPool)
+	limits, err := r.store.RateLimit.GetAll(ctx)
 
 	if err != nil {
 		return err
 	}
 
-	for _, limit := range limits {
+	for _, detail := range limits {
 
+		limit := detail.RateLimit
 		limit.TotalRequest = 0
 
 		limit.UpdatedBy = uuid.NullUUID{
@@ -122,7 +111,7 @@ func UpdateRateLimit(ctx context.Context) error {
 			Valid: true,
 		}
 
-		err := limit.Update(ctx, dbPool)
+		err := r.store.RateLimit.Update(ctx, &limit)
 
 		if err != nil {
 			return err
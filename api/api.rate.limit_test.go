@@ -0,0 +1,44 @@
+package api
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"corona/storage"
+	"testing"
+)
+
+func TestUpdateRateLimit(t *testing.T) {
+
+	ctx := context.Background()
+	memory := storage.NewMemory()
+
+	subscription := models.SubscriptionModel{SubscriptionType: "free", RequestPerDay: 10}
+	if err := memory.Subscription.Insert(ctx, &subscription); err != nil {
+		t.Fatal(err)
+	}
+
+	user := models.UserModel{SubscriptionId: subscription.Id, Name: "john", Email: "john@example.com"}
+	if err := memory.User.Insert(ctx, &user); err != nil {
+		t.Fatal(err)
+	}
+
+	limit := models.RateLimitModel{UserId: user.Id, TotalRequest: 7}
+	if err := memory.RateLimit.Insert(ctx, &limit); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewRateLimitModule(memory, helpers.NewLogger()).UpdateAll(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := memory.RateLimit.GetOneByUserId(ctx, user.Id)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updated.TotalRequest != 0 || !updated.UpdatedAt.Valid {
+		t.Errorf("rate limit was not reset: %+v", updated)
+	}
+}
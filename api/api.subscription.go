@@ -4,14 +4,14 @@ import (
 	"context"
 	"corona/helpers"
 	"corona/models"
-	"database/sql"
+	"corona/storage"
 	uuid "github.com/satori/go.uuid"
 	"net/http"
 )
 
 type (
 	SubscriptionModule struct {
-		db     *sql.DB
+		store  storage.Store
 		logger *helpers.Logger
 		name   string
 	}
@@ -26,9 +26,9 @@ type (
 	}
 )
 
-func NewSubscriptionModule(db *sql.DB, logger *helpers.Logger) *SubscriptionModule {
+func NewSubscriptionModule(store storage.Store, logger *helpers.Logger) *SubscriptionModule {
 	return &SubscriptionModule{
-		db:     db,
+		store:  store,
 		logger: logger,
 		name:   "module/subscription",
 	}
@@ -36,7 +36,7 @@ func NewSubscriptionModule(db *sql.DB, logger *helpers.Logger) *SubscriptionModu
 
 func (s SubscriptionModule) List(ctx context.Context) (interface{}, *helpers.Error) {
 
-	subscriptions, err := models.GetAllSubscription(ctx, s.db)
+	subscriptions, err := s.store.Subscription.GetAll(ctx)
 
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, s.name, "List/GetAllSubscription",
@@ -53,7 +53,7 @@ func (s SubscriptionModule) List(ctx context.Context) (interface{}, *helpers.Err
 
 func (s SubscriptionModule) Detail(ctx context.Context, param SubscriptionDetailParam) (interface{}, *helpers.Error) {
 
-	subscription, err := models.GetOneSubscription(ctx, s.db, param.Id)
+	subscription, err := s.store.Subscription.GetOne(ctx, param.Id)
 
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, s.name, "Detail/GetOneSubscription",
@@ -72,7 +72,7 @@ func (s SubscriptionModule) Add(ctx context.Context, param SubscriptionAddParam)
 		CreatedBy:        uuid.NewV4(),
 	}
 
-	err := subscription.Insert(ctx, s.db)
+	err := s.store.Subscription.Insert(ctx, &subscription)
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, s.name, "Add/Insert", helpers.InternalServerError,
 			http.StatusInternalServerError)
@@ -4,7 +4,7 @@ import (
 	"context"
 	"corona/helpers"
 	"corona/models"
-	"database/sql"
+	"corona/storage"
 	uuid "github.com/satori/go.uuid"
 	"net/http"
 	"time"
@@ -12,7 +12,7 @@ import (
 
 type (
 	TokenModule struct {
-		db     *sql.DB
+		store  storage.Store
 		logger *helpers.Logger
 		name   string
 	}
@@ -28,9 +28,9 @@ type (
 	}
 )
 
-func NewTokenModule(db *sql.DB, logger *helpers.Logger) *TokenModule {
+func NewTokenModule(store storage.Store, logger *helpers.Logger) *TokenModule {
 	return &TokenModule{
-		db:     db,
+		store:  store,
 		logger: logger,
 		name:   "module/token",
 	}
@@ -38,7 +38,7 @@ func NewTokenModule(db *sql.DB, logger *helpers.Logger) *TokenModule {
 
 func (t TokenModule) List(ctx context.Context) (interface{}, *helpers.Error) {
 
-	tokens, err := models.GetAllToken(ctx, t.db)
+	tokens, err := t.store.Token.GetAll(ctx)
 
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, t.name, "List/GetAllToken",
@@ -47,12 +47,7 @@ func (t TokenModule) List(ctx context.Context) (interface{}, *helpers.Error) {
 
 	var tokenResponses []models.TokenResponse
 	for _, token := range tokens {
-		response, err := token.Response(ctx, t.db, t.logger)
-		if err != nil {
-			return nil, helpers.ErrorWrap(err, t.name, "List/token.Response",
-				helpers.InternalServerError, http.StatusInternalServerError)
-		}
-		tokenResponses = append(tokenResponses, response)
+		tokenResponses = append(tokenResponses, token.Response())
 	}
 
 	return tokenResponses, nil
@@ -60,20 +55,14 @@ func (t TokenModule) List(ctx context.Context) (interface{}, *helpers.Error) {
 
 func (t TokenModule) Detail(ctx context.Context, param TokenDetailParam) (interface{}, *helpers.Error) {
 
-	token, err := models.GetOneToken(ctx, t.db, param.Id)
+	token, err := t.store.Token.GetOne(ctx, param.Id)
 
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, t.name, "Detail/GetOneToken",
 			helpers.InternalServerError, http.StatusInternalServerError)
 	}
 
-	response, err := token.Response(ctx, t.db, t.logger)
-	if err != nil {
-		return nil, helpers.ErrorWrap(err, t.name, "Detail/token.Response",
-			helpers.InternalServerError, http.StatusInternalServerError)
-	}
-
-	return response, nil
+	return token.Response(), nil
 
 }
 
@@ -86,19 +75,21 @@ func (t TokenModule) Add(ctx context.Context, param TokenAddParam) (interface{},
 		CreatedBy: uuid.NewV4(),
 	}
 
-	err := token.Insert(ctx, t.db)
+	err := t.store.Token.Insert(ctx, &token)
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, t.name, "Add/Insert", helpers.InternalServerError,
 			http.StatusInternalServerError)
 	}
 
-	response, err := token.Response(ctx, t.db, t.logger)
+	user, err := t.store.User.GetOne(ctx, token.UserId)
 
 	if err != nil {
-		return nil, helpers.ErrorWrap(err, t.name, "Add/token.Response", helpers.InternalServerError,
+		return nil, helpers.ErrorWrap(err, t.name, "Add/GetOneUser", helpers.InternalServerError,
 			http.StatusInternalServerError)
 	}
 
-	return response, nil
+	detail := models.TokenDetailModel{Token: token, User: user}
+
+	return detail.Response(), nil
 
 }
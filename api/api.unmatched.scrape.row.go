@@ -0,0 +1,157 @@
+package api
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"corona/storage"
+	"database/sql"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"net/http"
+	"strings"
+)
+
+type (
+	UnmatchedScrapeRowModule struct {
+		store  storage.Store
+		logger *helpers.Logger
+		name   string
+	}
+
+	UnmatchedScrapeRowListParam struct {
+		All bool `json:"all" schema:"all"`
+	}
+
+	UnmatchedScrapeRowResolveParam struct {
+		Id        uuid.UUID `json:"-"`
+		CountryId uuid.UUID `json:"country_id" validate:"required"`
+	}
+)
+
+func NewUnmatchedScrapeRowModule(store storage.Store, logger *helpers.Logger) *UnmatchedScrapeRowModule {
+	return &UnmatchedScrapeRowModule{
+		store:  store,
+		logger: logger,
+		name:   "module/unmatched.scrape.row",
+	}
+}
+
+func (s UnmatchedScrapeRowModule) List(ctx context.Context, param UnmatchedScrapeRowListParam) (
+	interface{}, *helpers.Error) {
+
+	rows, err := s.store.Country.GetAllUnmatchedRow(ctx, param.All)
+
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, s.name, "List/GetAllUnmatchedScrapeRow",
+			helpers.InternalServerError, http.StatusInternalServerError)
+	}
+
+	var rowResponses []models.UnmatchedScrapeRowResponse
+	for _, row := range rows {
+		rowResponses = append(rowResponses, row.Response())
+	}
+
+	return rowResponses, nil
+}
+
+// Resolve maps the row's name to a country by recording it as an alias for the
+// row's source, which the next scrape run picks up.
+func (s UnmatchedScrapeRowModule) Resolve(ctx context.Context, param UnmatchedScrapeRowResolveParam) (
+	interface{}, *helpers.Error) {
+
+	row, err := s.store.Country.GetOneUnmatchedRow(ctx, param.Id)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helpers.ErrorWrap(err, s.name, "Resolve/GetOneUnmatchedScrapeRow",
+				helpers.NotFoundMessage, http.StatusNotFound)
+		}
+		return nil, helpers.ErrorWrap(err, s.name, "Resolve/GetOneUnmatchedScrapeRow",
+			helpers.InternalServerError, http.StatusInternalServerError)
+	}
+
+	if row.ResolvedAt.Valid {
+		return nil, helpers.ErrorWrap(errors.New("row already resolved"), s.name, "Resolve/ResolvedAt",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	_, err = s.store.Country.GetOne(ctx, param.CountryId)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, helpers.ErrorWrap(err, s.name, "Resolve/GetOneCountry",
+				helpers.BadRequestMessage, http.StatusBadRequest)
+		}
+		return nil, helpers.ErrorWrap(err, s.name, "Resolve/GetOneCountry",
+			helpers.InternalServerError, http.StatusInternalServerError)
+	}
+
+	alias := models.CountryAliasModel{
+		CountryId: param.CountryId,
+		Alias:     row.Name,
+		Source:    row.Source,
+		CreatedBy: uuid.NewV4(),
+	}
+
+	row.CountryId = uuid.NullUUID{
+		UUID:  param.CountryId,
+		Valid: true,
+	}
+	row.ResolvedBy = uuid.NullUUID{
+		UUID:  uuid.NewV4(),
+		Valid: true,
+	}
+
+	// The alias and the row are written together, so a failed resolve can be
+	// retried. An alias left by an earlier attempt is kept if it points to the
+	// same country.
+	err = s.store.Transaction(ctx, func(tx storage.Store) error {
+
+		aliases, err := tx.Country.GetAllAliasBySource(ctx, row.Source)
+
+		if err != nil {
+			return err
+		}
+
+		existing, found := findAlias(aliases, row.Name, row.Source)
+
+		if found && existing.CountryId != param.CountryId {
+			return errors.Wrapf(helpers.ErrDuplicate, "alias %q of source %q points to country %s",
+				row.Name, row.Source, existing.CountryId)
+		}
+
+		if !found {
+			if err := tx.Country.InsertAlias(ctx, &alias); err != nil {
+				return err
+			}
+		}
+
+		return tx.Country.ResolveUnmatchedRow(ctx, &row)
+	})
+
+	if err != nil {
+		if errors.Cause(err) == helpers.ErrDuplicate {
+			return nil, helpers.ErrorWrap(err, s.name, "Resolve/Transaction", helpers.AliasExistsMessage,
+				http.StatusConflict)
+		}
+		return nil, helpers.ErrorWrap(err, s.name, "Resolve/Transaction", helpers.InternalServerError,
+			http.StatusInternalServerError)
+	}
+
+	return row.Response(), nil
+
+}
+
+// findAlias returns the alias of aliases recorded as name for source, ignoring
+// case as the unique index does.
+func findAlias(aliases []models.CountryAliasModel, name, source string) (models.CountryAliasModel, bool) {
+
+	for _, alias := range aliases {
+		if alias.Source == source && strings.EqualFold(alias.Alias, name) {
+			return alias, true
+		}
+	}
+
+	return models.CountryAliasModel{}, false
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"corona/storage"
+	"net/http"
+	"testing"
+)
+
+func TestUnmatchedScrapeRowResolve(t *testing.T) {
+
+	ctx := context.Background()
+	store := storage.NewMemory()
+
+	continent := models.ContinentModel{Name: "Asia"}
+	if err := store.Continent.Insert(ctx, &continent); err != nil {
+		t.Fatal(err)
+	}
+
+	countries := make(map[string]models.CountryModel)
+	for _, name := range []string{"South Korea", "North Korea"} {
+		country := models.CountryModel{ContinentId: continent.Id, Name: name}
+		if err := store.Country.Insert(ctx, &country); err != nil {
+			t.Fatal(err)
+		}
+		countries[name] = country
+	}
+
+	rows := make(map[string]models.UnmatchedScrapeRowModel)
+	for _, name := range []string{"S. Korea", "DPRK"} {
+		row := models.UnmatchedScrapeRowModel{Source: "worldometers", Name: name}
+		if err := store.Country.UpsertUnmatchedRow(ctx, &row); err != nil {
+			t.Fatal(err)
+		}
+		rows[name] = row
+	}
+
+	// Aliases left behind by an earlier attempt, one of them to another country.
+	for alias, country := range map[string]string{"s. korea": "South Korea", "DPRK": "South Korea"} {
+		model := models.CountryAliasModel{CountryId: countries[country].Id, Alias: alias, Source: "worldometers"}
+		if err := store.Country.InsertAlias(ctx, &model); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	module := NewUnmatchedScrapeRowModule(store, helpers.NewLogger())
+
+	_, err := module.Resolve(ctx, UnmatchedScrapeRowResolveParam{
+		Id:        rows["S. Korea"].Id,
+		CountryId: countries["South Korea"].Id,
+	})
+	if err != nil {
+		t.Fatalf("resolving to the country of the existing alias got %v", err.Err)
+	}
+
+	_, err = module.Resolve(ctx, UnmatchedScrapeRowResolveParam{
+		Id:        rows["DPRK"].Id,
+		CountryId: countries["North Korea"].Id,
+	})
+	if err == nil || err.StatusCode != http.StatusConflict {
+		t.Errorf("resolving to another country than the existing alias got %v, want a conflict", err)
+	}
+
+	pending, errList := store.Country.GetAllUnmatchedRow(ctx, false)
+	if errList != nil {
+		t.Fatal(errList)
+	}
+	if len(pending) != 1 || pending[0].Name != "DPRK" {
+		t.Errorf("pending rows = %+v, want DPRK only", pending)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"corona/helpers"
 	"corona/models"
+	"corona/storage"
 	"corona/util"
 	"database/sql"
 	"encoding/base64"
@@ -16,7 +17,7 @@ import (
 
 type (
 	UserModule struct {
-		db     *sql.DB
+		store  storage.Store
 		logger *helpers.Logger
 		name   string
 	}
@@ -44,9 +45,9 @@ type (
 	}
 )
 
-func NewUserModule(db *sql.DB, logger *helpers.Logger) *UserModule {
+func NewUserModule(store storage.Store, logger *helpers.Logger) *UserModule {
 	return &UserModule{
-		db:     db,
+		store:  store,
 		logger: logger,
 		name:   "module/user",
 	}
@@ -76,20 +77,22 @@ func (u UserModule) Register(ctx context.Context, param UserRegisterParam) (inte
 		CreatedBy:      uuid.NewV4(),
 	}
 
-	err = user.Insert(ctx, u.db)
+	err = u.store.User.Insert(ctx, &user)
 
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, u.name, "Register/user.Insert", helpers.InternalServerError,
 			http.StatusInternalServerError)
 	}
 
-	userResponse, err := user.Response(ctx, u.db, u.logger)
+	subscription, err := u.store.Subscription.GetOne(ctx, user.SubscriptionId)
 
 	if err != nil {
-		return nil, helpers.ErrorWrap(err, u.name, "Register/user.Response", helpers.InternalServerError,
+		return nil, helpers.ErrorWrap(err, u.name, "Register/GetOneSubscription", helpers.InternalServerError,
 			http.StatusInternalServerError)
 	}
 
+	detail := models.UserDetailModel{User: user, Subscription: subscription}
+
 	tokenKey := util.GenerateToken()
 
 	expiredAt := time.Now().AddDate(0, 1, 0)
@@ -103,7 +106,7 @@ func (u UserModule) Register(ctx context.Context, param UserRegisterParam) (inte
 		CreatedBy: uuid.NewV4(),
 	}
 
-	err = token.Insert(ctx, u.db)
+	err = u.store.Token.Insert(ctx, &token)
 
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, u.name, "Register/token.Insert", helpers.InternalServerError,
@@ -116,10 +119,10 @@ func (u UserModule) Register(ctx context.Context, param UserRegisterParam) (inte
 		CreatedBy:    uuid.NewV4(),
 	}
 
-	err = rateLimit.Insert(ctx, u.db)
+	err = u.store.RateLimit.Insert(ctx, &rateLimit)
 
 	userWithToken := UserWithToken{
-		User:  userResponse,
+		User:  detail.Response(),
 		Token: tokenKey,
 	}
 
@@ -128,7 +131,7 @@ func (u UserModule) Register(ctx context.Context, param UserRegisterParam) (inte
 
 func (u UserModule) Login(ctx context.Context, param UserLoginParam) (interface{}, *helpers.Error) {
 
-	user, err := models.GetOneUserByEmail(ctx, u.db, param.Email)
+	user, err := u.store.User.GetOneByEmail(ctx, param.Email)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -139,7 +142,7 @@ func (u UserModule) Login(ctx context.Context, param UserLoginParam) (interface{
 			http.StatusInternalServerError)
 	}
 
-	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(param.Password))
+	err = bcrypt.CompareHashAndPassword([]byte(user.User.Password), []byte(param.Password))
 
 	if err != nil {
 		if err != nil {
@@ -150,55 +153,46 @@ func (u UserModule) Login(ctx context.Context, param UserLoginParam) (interface{
 		}
 	}
 
-	userResponse, err := user.Response(ctx, u.db, u.logger)
-
-	if err != nil {
-		return nil, helpers.ErrorWrap(err, u.name, "Login/user.Response", helpers.InternalServerError,
-			http.StatusInternalServerError)
-	}
-
-	return userResponse, nil
+	return user.Response(), nil
 
 }
 
 func (u UserModule) List(ctx context.Context, filter helpers.Filter) (interface{}, *helpers.Error) {
 
-	users, err := models.GetAllUser(ctx, u.db, filter)
+	users, err := u.store.User.GetAll(ctx, filter)
 
 	if err != nil {
-		return nil, helpers.ErrorWrap(err, u.name, "List/GetAllUser",
-			helpers.InternalServerError, http.StatusInternalServerError)
+		return nil, helpers.FilterErrorWrap(err, u.name, "List/GetAllUser")
+	}
+
+	total, err := u.store.User.Count(ctx, filter)
+
+	if err != nil {
+		return nil, helpers.FilterErrorWrap(err, u.name, "List/CountUser")
 	}
 
 	var userResponses []models.UserResponse
 	for _, user := range users {
-		response, err := user.Response(ctx, u.db, u.logger)
-		if err != nil {
-			return nil, helpers.ErrorWrap(err, u.name, "List/user.Response",
-				helpers.InternalServerError, http.StatusInternalServerError)
-		}
-		userResponses = append(userResponses, response)
+		userResponses = append(userResponses, user.Response())
+	}
+
+	page := helpers.NewPage(userResponses, filter, len(users), total)
+	if page.More() {
+		page.Cursor = users[len(users)-1].Cursor(filter)
 	}
 
-	return userResponses, nil
+	return page, nil
 }
 
 func (u UserModule) Detail(ctx context.Context, param UserDetailParam) (interface{}, *helpers.Error) {
 
-	user, err := models.GetOneUser(ctx, u.db, param.Id)
+	user, err := u.store.User.GetOne(ctx, param.Id)
 
 	if err != nil {
 		return nil, helpers.ErrorWrap(err, u.name, "Detail/GetOneUser",
 			helpers.InternalServerError, http.StatusInternalServerError)
 	}
 
-	response, err := user.Response(ctx, u.db, u.logger)
-
-	if err != nil {
-		return nil, helpers.ErrorWrap(err, u.name, "Detail/user.Response",
-			helpers.InternalServerError, http.StatusInternalServerError)
-	}
-
-	return response, nil
+	return user.Response(), nil
 
 }
@@ -2,15 +2,35 @@ package api
 
 import (
 	"corona/helpers"
-	"database/sql"
+	"corona/scrapper"
+	"corona/storage"
 )
 
-var (
-	dbPool *sql.DB
-	logger *helpers.Logger
+type (
+	// Services holds a module per resource, all sharing the same store.
+	Services struct {
+		Corona             *CoronaModule
+		Country            *CountryModule
+		CountryAlias       *CountryAliasModule
+		Continent          *ContinentModule
+		User               *UserModule
+		RateLimit          *RateLimitModule
+		Subscription       *SubscriptionModule
+		Token              *TokenModule
+		UnmatchedScrapeRow *UnmatchedScrapeRowModule
+	}
 )
 
-func Init(db *sql.DB, log *helpers.Logger) {
-	dbPool = db
-	logger = log
+func NewServices(store storage.Store, scrapper *scrapper.Scrapper, logger *helpers.Logger) Services {
+	return Services{
+		Corona:             NewCoronaModule(store, scrapper, logger),
+		Country:            NewCountryModule(store, logger),
+		CountryAlias:       NewCountryAliasModule(store, logger),
+		Continent:          NewContinentModule(store, logger),
+		User:               NewUserModule(store, logger),
+		RateLimit:          NewRateLimitModule(store, logger),
+		Subscription:       NewSubscriptionModule(store, logger),
+		Token:              NewTokenModule(store, logger),
+		UnmatchedScrapeRow: NewUnmatchedScrapeRowModule(store, logger),
+	}
 }
@@ -0,0 +1,100 @@
+// Package app wires the store, scrapper, services and middleware together. Each
+// App owns its dependencies, so several of them can live side by side.
+package app
+
+import (
+	"corona/api"
+	"corona/cron"
+	"corona/helpers"
+	"corona/middleware"
+	"corona/routers"
+	"corona/scrapper"
+	"corona/storage"
+	"database/sql"
+	"github.com/gorilla/mux"
+	"time"
+)
+
+type (
+	Config struct {
+		Port            int
+		ReadTimeout     time.Duration
+		WriteTimeout    time.Duration
+		ShutdownTimeout time.Duration
+		Database        helpers.DBOptions
+		Scrapper        scrapper.Option
+		Cron            cron.Option
+	}
+
+	App struct {
+		Config   Config
+		Logger   *helpers.Logger
+		DB       *sql.DB
+		Store    storage.Store
+		Scrapper *scrapper.Scrapper
+		Services api.Services
+
+		middleware *middleware.Middleware
+	}
+)
+
+// New connects to the database of cfg and builds an App backed by it.
+func New(cfg Config, logger *helpers.Logger) (*App, error) {
+
+	db, err := helpers.InitDB(cfg.Database)
+
+	if err != nil {
+		return nil, err
+	}
+
+	app, err := NewWithStore(cfg, logger, storage.NewPostgres(db))
+
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	app.DB = db
+
+	return app, nil
+}
+
+// NewWithStore builds an App on top of store, e.g. storage.NewMemory in tests.
+// Its DB is left nil.
+func NewWithStore(cfg Config, logger *helpers.Logger, store storage.Store) (*App, error) {
+
+	s, err := scrapper.NewScrapper(store, logger, cfg.Scrapper)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &App{
+		Config:     cfg,
+		Logger:     logger,
+		Store:      store,
+		Scrapper:   s,
+		Services:   api.NewServices(store, s, logger),
+		middleware: middleware.NewMiddleware(store, logger),
+	}, nil
+}
+
+// Router builds the HTTP routes of the API.
+func (a *App) Router() *mux.Router {
+	return routers.NewHandlers(a.Services, a.middleware, a.Logger).Router()
+}
+
+// Scheduler builds the cron tasks of the configuration.
+func (a *App) Scheduler() *cron.Scheduler {
+	return cron.NewScheduler(a.Services, a.Logger, a.Config.Cron)
+}
+
+// Close releases the database connections, if any.
+func (a *App) Close() error {
+
+	if a.DB == nil {
+		return nil
+	}
+
+	return a.DB.Close()
+}
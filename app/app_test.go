@@ -0,0 +1,323 @@
+package app
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"corona/scrapper"
+	"corona/storage"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestApp(t *testing.T) *App {
+	t.Helper()
+
+	a, err := NewWithStore(Config{}, helpers.NewLogger(), storage.NewMemory())
+
+	if err != nil {
+		t.Fatalf("NewWithStore: %v", err)
+	}
+
+	return a
+}
+
+// register signs a user up on a and returns their token.
+func register(t *testing.T, a *App) string {
+	t.Helper()
+
+	subscription := models.SubscriptionModel{SubscriptionType: "free", RequestPerDay: 10}
+	if err := a.Store.Subscription.Insert(context.Background(), &subscription); err != nil {
+		t.Fatal(err)
+	}
+
+	body := fmt.Sprintf(`{"subscription_id": %q, "name": "tester", "email": "tester@example.com",
+		"password": "secret", "confirm_password": "secret"}`, subscription.Id)
+
+	w := httptest.NewRecorder()
+	a.Router().ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/register", strings.NewReader(body)))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("register returned %d: %s", w.Code, w.Body)
+	}
+
+	var resp struct {
+		Data struct {
+			Token string `json:"token"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+
+	return resp.Data.Token
+}
+
+func TestAppsAreIndependent(t *testing.T) {
+
+	t.Parallel()
+
+	first, second := newTestApp(t), newTestApp(t)
+
+	token := register(t, first)
+
+	for _, test := range []struct {
+		app  *App
+		want int
+	}{
+		{first, http.StatusNotFound},
+		{second, http.StatusUnauthorized},
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/coronavirus/world", nil)
+		r.Header.Set("Token", token)
+
+		w := httptest.NewRecorder()
+		test.app.Router().ServeHTTP(w, r)
+
+		if w.Code != test.want {
+			t.Errorf("got status %d, want %d: %s", w.Code, test.want, w.Body)
+		}
+	}
+}
+
+// TestUnboundedLists checks that the lists without range columns reject bounds
+// rather than ignoring them.
+func TestUnboundedLists(t *testing.T) {
+
+	a := newTestApp(t)
+
+	for _, target := range []string{"/api/v1/countries", "/api/v1/continents"} {
+
+		w := httptest.NewRecorder()
+		a.Router().ServeHTTP(w, httptest.NewRequest(http.MethodGet, target+"?population_gte=1", nil))
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s with a bound got status %d, want %d: %s", target, w.Code, http.StatusBadRequest, w.Body)
+		}
+	}
+}
+
+func TestCSVExport(t *testing.T) {
+
+	t.Parallel()
+
+	a := newTestApp(t)
+	ctx := context.Background()
+	token := register(t, a)
+
+	europe := models.ContinentModel{Name: "Europe", Code: "EU"}
+	if err := a.Store.Continent.Insert(ctx, &europe); err != nil {
+		t.Fatal(err)
+	}
+
+	country := models.CountryModel{ContinentId: europe.Id, Name: "Iceland, Republic of"}
+	if err := a.Store.Country.Insert(ctx, &country); err != nil {
+		t.Fatal(err)
+	}
+
+	data := models.CoronaModel{CountryId: country.Id}
+	data.TotalCases.Int64, data.TotalCases.Valid = 1800, true
+	if err := a.Store.Corona.Insert(ctx, &data); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func(target, accept string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r.Header.Set("Token", token)
+		r.Header.Set("Accept", accept)
+
+		w := httptest.NewRecorder()
+		a.Router().ServeHTTP(w, r)
+
+		return w
+	}
+
+	w := get("/api/v1/continents?format=csv", "")
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+
+	if w.Header().Get("Content-Type") != "text/csv" || w.Header().Get("X-Total-Count") != "1" || len(lines) != 2 ||
+		lines[0] != "id,name,code,created_by,created_at,updated_by,updated_at" ||
+		!strings.HasPrefix(lines[1], europe.Id.String()+",Europe,EU,") {
+		t.Errorf("got %s %v:\n%s", w.Header().Get("Content-Type"), w.Header(), w.Body)
+	}
+
+	w = get("/api/v1/coronavirus", "text/html;q=0.9, text/csv")
+	lines = strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+
+	if w.Code != http.StatusOK || len(lines) != 2 ||
+		!strings.HasPrefix(lines[0], "id,country.id,country.continent.id,country.continent.name,") ||
+		!strings.Contains(lines[1], `,"Iceland, Republic of",`) || !strings.Contains(lines[1], ",1800,,") {
+		t.Errorf("got %d:\n%s", w.Code, w.Body)
+	}
+
+	w = get("/api/v1/coronavirus?format=csv&fields=country.name,total_cases", "")
+	if want := "country.name,total_cases\n\"Iceland, Republic of\",1800\n"; w.Body.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", w.Body, want)
+	}
+
+	w = get("/api/v1/coronavirus?format=csv&embed=none", "")
+	if want := "id,country,total_cases,"; !strings.HasPrefix(w.Body.String(), want) {
+		t.Errorf("got\n%s\nwant a header starting with %s", w.Body, want)
+	}
+
+	if w = get("/api/v1/coronavirus?embed=some", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("got %d for an unknown embed, want %d", w.Code, http.StatusBadRequest)
+	}
+
+	if w = get("/api/v1/countries", "*/*"); w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("got %s by default, want JSON", w.Header().Get("Content-Type"))
+	}
+
+	if w = get("/api/v1/countries?format=xml", ""); w.Code != http.StatusNotAcceptable {
+		t.Errorf("got %d for an unknown format, want %d", w.Code, http.StatusNotAcceptable)
+	}
+
+	if w = get("/api/v1/countries?format=csv&limit=ten", ""); w.Code != http.StatusBadRequest ||
+		w.Header().Get("Content-Type") != "application/json" {
+		t.Errorf("errors should be JSON, got %d %s", w.Code, w.Header().Get("Content-Type"))
+	}
+}
+
+func TestGeoJSON(t *testing.T) {
+
+	t.Parallel()
+
+	a := newTestApp(t)
+	ctx := context.Background()
+	token := register(t, a)
+
+	europe := models.ContinentModel{Name: "Europe", Code: "EU"}
+	if err := a.Store.Continent.Insert(ctx, &europe); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, code := range []string{"IS", ""} {
+
+		country := models.CountryModel{ContinentId: europe.Id, Name: "Country " + code, Code: code}
+		if err := a.Store.Country.Insert(ctx, &country); err != nil {
+			t.Fatal(err)
+		}
+
+		data := models.CoronaModel{CountryId: country.Id}
+		data.TotalCases.Int64, data.TotalCases.Valid = 1800, true
+		if err := a.Store.Corona.Insert(ctx, &data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	get := func(target string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		r.Header.Set("Token", token)
+
+		w := httptest.NewRecorder()
+		a.Router().ServeHTTP(w, r)
+
+		return w
+	}
+
+	w := get("/api/v1/coronavirus?format=geojson")
+
+	var collection struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry *struct {
+				Type        string    `json:"type"`
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]interface{} `json:"properties"`
+		} `json:"features"`
+	}
+	if err := json.NewDecoder(w.Body).Decode(&collection); err != nil {
+		t.Fatal(err)
+	}
+
+	if w.Header().Get("Content-Type") != "application/geo+json" || collection.Type != "FeatureCollection" ||
+		len(collection.Features) != 2 {
+		t.Fatalf("got %s %+v", w.Header().Get("Content-Type"), collection)
+	}
+
+	// Countries are sorted by name, the one without a code first.
+	unknown, iceland := collection.Features[0], collection.Features[1]
+
+	if unknown.Geometry != nil {
+		t.Errorf("a country without a code should have no geometry, got %+v", unknown.Geometry)
+	}
+
+	if iceland.Geometry == nil || iceland.Geometry.Type != "Point" ||
+		iceland.Geometry.Coordinates[0] > -10 || iceland.Geometry.Coordinates[1] < 60 {
+		t.Errorf("got geometry %+v for Iceland", iceland.Geometry)
+	}
+
+	if iceland.Properties["total_cases"] != 1800.0 || iceland.Properties["country.code"] != "IS" ||
+		iceland.Properties["derived.cases_per_million"] != nil {
+		t.Errorf("got properties %v", iceland.Properties)
+	}
+
+	for _, target := range []string{"/api/v1/continents?format=geojson", "/api/v1/continents?format=geojson&search=none"} {
+		if w = get(target); w.Code != http.StatusNotAcceptable {
+			t.Errorf("got %d for %s, want %d", w.Code, target, http.StatusNotAcceptable)
+		}
+	}
+
+	if w = get("/api/v1/countries?format=geojson&search=none"); w.Code != http.StatusOK {
+		t.Errorf("got %d for no countries, want %d", w.Code, http.StatusOK)
+	}
+}
+
+// TestUnsupportedFormat checks that a format a route does not offer is refused
+// before the route runs or counts against the rate limit.
+func TestUnsupportedFormat(t *testing.T) {
+
+	ctx := context.Background()
+
+	scrapes := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scrapes++
+		http.ServeFile(w, r, "../scrapper/testdata/worldometers.html")
+	}))
+	defer server.Close()
+
+	a, err := NewWithStore(Config{
+		Scrapper: scrapper.Option{SourceOption: map[string]interface{}{"url": server.URL}},
+	}, helpers.NewLogger(), storage.NewMemory())
+	if err != nil {
+		t.Fatalf("NewWithStore: %v", err)
+	}
+
+	token := register(t, a)
+
+	for _, test := range []struct {
+		method string
+		target string
+	}{
+		{http.MethodPost, "/api/v1/coronavirus?format=geojson"},
+		{http.MethodGet, "/api/v1/coronavirus/world?format=geojson"},
+		{http.MethodGet, "/api/v1/coronavirus/top?format=csv"},
+	} {
+		r := httptest.NewRequest(test.method, test.target, nil)
+		r.Header.Set("Token", token)
+
+		w := httptest.NewRecorder()
+		a.Router().ServeHTTP(w, r)
+
+		if w.Code != http.StatusNotAcceptable {
+			t.Errorf("%s %s = %d, want %d", test.method, test.target, w.Code, http.StatusNotAcceptable)
+		}
+	}
+
+	if scrapes != 0 {
+		t.Errorf("scraped %d times, want none", scrapes)
+	}
+
+	rateLimits, err := a.Store.RateLimit.GetAll(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rateLimits) != 1 || rateLimits[0].RateLimit.TotalRequest != 0 {
+		t.Errorf("got rate limits %+v, want no request counted", rateLimits)
+	}
+}
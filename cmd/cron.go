@@ -2,11 +2,7 @@ package cmd
 
 import (
 	"context"
-	"corona/api"
-	"corona/cron"
-	"corona/scrapper"
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 	"os"
 	"os/signal"
 	"syscall"
@@ -15,21 +11,15 @@ import (
 var cronCmd = &cobra.Command{
 	Use: "cron",
 	PreRun: func(cmd *cobra.Command, args []string) {
-
-		initDB()
-		initLogger()
-		scrapper.Init(dbPool, logger)
-
-		cron.Init(logger, dbPool, cron.InitOption{
-			CronTask: viper.GetStringMap("cron"),
-		})
-
-		api.Init(dbPool, logger)
-
+		initApp()
 	},
 	Run: func(cmd *cobra.Command, args []string) {
+		defer application.Close()
+
+		logger := application.Logger
+
 		ctx := context.Background()
-		tasks, runningCron := cron.Run(ctx)
+		tasks, runningCron := application.Scheduler().Run(ctx)
 		logger.Out.Println("Cron up & running.")
 		sigint := make(chan os.Signal, 1)
 		signal.Notify(sigint, syscall.SIGINT, syscall.SIGTERM)
@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"corona/migrations"
+	"fmt"
+	"github.com/spf13/cobra"
+	"os"
+	"time"
+)
+
+var migrateSteps int
+
+var migrateCmd = &cobra.Command{
+	Use:       "migrate up|down|status",
+	Short:     "Apply, roll back or list the embedded schema migrations",
+	Args:      cobra.ExactValidArgs(1),
+	ValidArgs: []string{"up", "down", "status"},
+	PreRun: func(cmd *cobra.Command, args []string) {
+		initApp()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		defer application.Close()
+
+		logger := application.Logger
+
+		ctx := context.Background()
+
+		var err error
+
+		switch args[0] {
+		case "up":
+			var applied []migrations.Migration
+			applied, err = migrations.Up(ctx, application.DB)
+			for _, migration := range applied {
+				logger.Out.WithField("migration", migration.String()).Println("Migration applied.")
+			}
+			if err == nil && len(applied) == 0 {
+				logger.Out.Println("Schema is up to date.")
+			}
+		case "down":
+			var rolledBack []migrations.Migration
+			rolledBack, err = migrations.Down(ctx, application.DB, migrateSteps)
+			for _, migration := range rolledBack {
+				logger.Out.WithField("migration", migration.String()).Println("Migration rolled back.")
+			}
+		case "status":
+			var statuses []migrations.Status
+			statuses, err = migrations.GetStatus(ctx, application.DB)
+			for _, status := range statuses {
+				appliedAt := "pending"
+				if status.AppliedAt != nil {
+					appliedAt = status.AppliedAt.Format(time.RFC3339)
+				}
+				fmt.Printf("%-40s %s\n", status.Migration.String(), appliedAt)
+			}
+		}
+
+		if err != nil {
+			logger.Err.WithError(err).Println("Migration failed.")
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	migrateCmd.Flags().IntVar(&migrateSteps, "steps", 1, "number of migrations to roll back with down")
+}
@@ -17,12 +17,10 @@ package cmd
 
 import (
 	"context"
-	"corona/api"
+	"corona/app"
+	"corona/cron"
 	"corona/helpers"
-	"corona/middleware"
-	"corona/routers"
 	"corona/scrapper"
-	"database/sql"
 	"fmt"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/sirupsen/logrus"
@@ -36,31 +34,27 @@ import (
 )
 
 var (
-	cfgFile string
-	logger  *helpers.Logger
-	dbPool  *sql.DB
+	cfgFile     string
+	application *app.App
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use: "corona",
 	PreRun: func(cmd *cobra.Command, args []string) {
-		initDB()
-		initLogger()
-		scrapper.Init(dbPool, logger)
-		api.Init(dbPool, logger)
-		helpers.Init(logger)
-		routers.Init(dbPool, logger)
-		middleware.Init(dbPool, logger)
+		initApp()
 	},
 	Run: func(cmd *cobra.Command, args []string) {
-		router := routers.InitHandlers()
+		defer application.Close()
+
+		cfg := application.Config
+		logger := application.Logger
 
 		server := &http.Server{
-			Addr:         fmt.Sprintf(":%d", viper.GetInt("app.port")),
-			ReadTimeout:  time.Duration(viper.GetInt("app.read_timeout")) * time.Second,
-			WriteTimeout: time.Duration(viper.GetInt("app.write_timeout")) * time.Second,
-			Handler:      router,
+			Addr:         fmt.Sprintf(":%d", cfg.Port),
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			Handler:      application.Router(),
 		}
 
 		idleConnsClosed := make(chan struct{})
@@ -68,8 +62,7 @@ var rootCmd = &cobra.Command{
 			sigint := make(chan os.Signal, 1)
 			signal.Notify(sigint, syscall.SIGINT, syscall.SIGTERM)
 			<-sigint
-			timeout := time.Duration(viper.GetInt("app.shutdown_timeout")) * time.Second
-			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
 			defer cancel()
 			if err := server.Shutdown(ctx); err != nil {
 				logger.Out.WithError(err).Println("Server shutdown error.")
@@ -78,7 +71,7 @@ var rootCmd = &cobra.Command{
 			close(idleConnsClosed)
 		}()
 
-		logger.Out.Println(fmt.Sprintf(`Server Listen And Serve On Port : %d`, viper.GetInt("app.port")))
+		logger.Out.Println(fmt.Sprintf(`Server Listen And Serve On Port : %d`, cfg.Port))
 		err := server.ListenAndServe()
 		if err != nil && err != http.ErrServerClosed {
 			logger.Out.Println(fmt.Sprintf(`Error Listen And Serve : %v`, err))
@@ -93,6 +86,8 @@ var rootCmd = &cobra.Command{
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	rootCmd.AddCommand(cronCmd)
+	rootCmd.AddCommand(migrateCmd)
+	rootCmd.AddCommand(seedCmd)
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
@@ -100,7 +95,7 @@ func Execute() {
 }
 
 func init() {
-	cobra.OnInitialize(initConfig, initDB)
+	cobra.OnInitialize(initConfig)
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
@@ -142,31 +137,53 @@ func initConfig() {
 	}
 }
 
-func initDB() {
-	dbOptions := helpers.DBOptions{
-		Host:        viper.GetString("database.host"),
-		Port:        viper.GetInt("database.port"),
-		Username:    viper.GetString("database.username"),
-		Password:    viper.GetString("database.password"),
-		DBName:      viper.GetString("database.name"),
-		SSLCert:     viper.GetString("database.sslcert"),
-		SSLKey:      viper.GetString("database.sslkey"),
-		SSLRootCert: viper.GetString("database.sslrootcert"),
-		SSLMode:     viper.GetString("database.sslmode"),
+// loadConfig reads the App configuration from viper.
+func loadConfig() app.Config {
+
+	source := viper.GetString("scrapper.source")
+
+	return app.Config{
+		Port:            viper.GetInt("app.port"),
+		ReadTimeout:     time.Duration(viper.GetInt("app.read_timeout")) * time.Second,
+		WriteTimeout:    time.Duration(viper.GetInt("app.write_timeout")) * time.Second,
+		ShutdownTimeout: time.Duration(viper.GetInt("app.shutdown_timeout")) * time.Second,
+		Database: helpers.DBOptions{
+			Host:        viper.GetString("database.host"),
+			Port:        viper.GetInt("database.port"),
+			Username:    viper.GetString("database.username"),
+			Password:    viper.GetString("database.password"),
+			DBName:      viper.GetString("database.name"),
+			SSLCert:     viper.GetString("database.sslcert"),
+			SSLKey:      viper.GetString("database.sslkey"),
+			SSLRootCert: viper.GetString("database.sslrootcert"),
+			SSLMode:     viper.GetString("database.sslmode"),
+		},
+		Scrapper: scrapper.Option{
+			Source:       source,
+			SourceOption: viper.GetStringMap(fmt.Sprintf("scrapper.%s", source)),
+		},
+		Cron: cron.Option{
+			CronTask: viper.GetStringMap("cron"),
+		},
 	}
+}
 
-	db, err := helpers.InitDB(dbOptions)
+func initApp() {
+	logger := newLogger()
+
+	a, err := app.New(loadConfig(), logger)
 
 	if err != nil {
-		logger.Err.Println(fmt.Sprintf("err connect : %v", err))
-		os.Exit(0)
+		logger.Err.Println(fmt.Sprintf("err init app : %v", err))
+		os.Exit(1)
 	}
 
-	dbPool = db
+	application = a
 }
 
-func initLogger() {
-	logger = helpers.NewLogger()
+func newLogger() *helpers.Logger {
+	logger := helpers.NewLogger()
 	logger.Out.Formatter = new(logrus.JSONFormatter)
 	logger.Err.Formatter = new(logrus.JSONFormatter)
+	return logger
 }
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"context"
+	"corona/seed"
+	"github.com/spf13/cobra"
+	"os"
+)
+
+var seedCmd = &cobra.Command{
+	Use:   "seed",
+	Short: "Load the continents, ISO 3166 countries and their aliases",
+	PreRun: func(cmd *cobra.Command, args []string) {
+		initApp()
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		defer application.Close()
+
+		logger := application.Logger
+
+		summary, err := seed.Run(context.Background(), application.DB)
+
+		if err != nil {
+			logger.Err.WithError(err).Println("Seed failed.")
+			os.Exit(1)
+		}
+
+		logger.Out.WithField("result", summary.Continents).Println("Continents seeded.")
+		logger.Out.WithField("result", summary.Countries).Println("Countries seeded.")
+		logger.Out.WithField("result", summary.Aliases).Println("Aliases seeded.")
+	},
+}
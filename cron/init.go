@@ -2,47 +2,48 @@ package cron
 
 import (
 	"context"
+	"corona/api"
 	"corona/helpers"
-	"database/sql"
 	"github.com/jasonlvhit/gocron"
 	"net/url"
 	"strconv"
 )
 
 type (
-	InitOption struct {
+	Option struct {
 		CronTask map[string]interface{}
 	}
 
+	// Scheduler runs the configured tasks against its services.
+	Scheduler struct {
+		logger *helpers.Logger
+		cfg    Option
+		tasks  map[string]TaskRunner
+	}
+
 	TaskRunner interface {
 		Parse(opt url.Values) error
 		Run(ctx context.Context)
 	}
 )
 
-var mapTasks = map[string]TaskRunner{
-	cronCasesUpdate:   &TaskCasesUpdate{},
-	cronRequestUpdate: &TaskRequestUpdate{},
-}
-
-var (
-	logger *helpers.Logger
-	cfg    InitOption
-	dbPool *sql.DB
-)
-
-func Init(lg *helpers.Logger, db *sql.DB, opt InitOption) {
-	logger = lg
-	cfg = opt
-	dbPool = db
+func NewScheduler(services api.Services, logger *helpers.Logger, opt Option) *Scheduler {
+	return &Scheduler{
+		logger: logger,
+		cfg:    opt,
+		tasks: map[string]TaskRunner{
+			cronCasesUpdate:   &TaskCasesUpdate{corona: services.Corona, logger: logger},
+			cronRequestUpdate: &TaskRequestUpdate{rateLimit: services.RateLimit, logger: logger},
+		},
+	}
 }
 
-func Run(ctx context.Context) (*gocron.Scheduler, chan bool) {
+func (sc *Scheduler) Run(ctx context.Context) (*gocron.Scheduler, chan bool) {
 
 	s := gocron.NewScheduler()
 
 	runningCron := make(chan bool)
-	taskCfg := cfg.CronTask["task"].([]interface{})
+	taskCfg := sc.cfg.CronTask["task"].([]interface{})
 
 	for _, v := range taskCfg {
 
@@ -53,11 +54,11 @@ func Run(ctx context.Context) (*gocron.Scheduler, chan bool) {
 
 		switch unit {
 		case "day":
-			s.Every(uint64(interval)).Day().At(time).Do(mapTasks[name].Run, ctx)
+			s.Every(uint64(interval)).Day().At(time).Do(sc.tasks[name].Run, ctx)
 		case "seconds":
-			s.Every(uint64(interval)).Seconds().At(time).Do(mapTasks[name].Run, ctx)
+			s.Every(uint64(interval)).Seconds().At(time).Do(sc.tasks[name].Run, ctx)
 		default:
-			logger.Err.WithField("name", name).Println("Missing cron unit.")
+			sc.logger.Err.WithField("name", name).Println("Missing cron unit.")
 		}
 	}
 
@@ -3,12 +3,15 @@ package cron
 import (
 	"context"
 	"corona/api"
+	"corona/helpers"
 	"net/url"
 )
 
 type (
 	TaskCasesUpdate struct {
-		Name string
+		Name   string
+		corona *api.CoronaModule
+		logger *helpers.Logger
 	}
 )
 
@@ -21,16 +24,19 @@ func (t *TaskCasesUpdate) Parse(opt url.Values) error {
 }
 
 func (t TaskCasesUpdate) Run(ctx context.Context) {
-	errLogger := logger.Err.WithField("cron", cronCasesUpdate)
-	outLogger := logger.Out.WithField("cron", cronCasesUpdate)
+	errLogger := t.logger.Err.WithField("cron", cronCasesUpdate)
+	outLogger := t.logger.Out.WithField("cron", cronCasesUpdate)
 
 	outLogger.Println("Updating data...")
 
-	err := api.UpdateAllCorona(ctx)
+	summary, err := t.corona.UpdateAll(ctx)
 
 	if err != nil {
-		errLogger.WithError(err).Errorln("Error on updating cases.")
+		errLogger.WithError(err).Errorln("Error on updating cases, nothing was stored.")
 		return
 	}
-	outLogger.Println("Cases updated successfully")
+	outLogger.WithField("inserted", summary.Inserted).
+		WithField("updated", summary.Updated).
+		WithField("unchanged", summary.Unchanged).
+		Println("Cases updated successfully")
 }
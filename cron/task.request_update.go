@@ -3,12 +3,15 @@ package cron
 import (
 	"context"
 	"corona/api"
+	"corona/helpers"
 	"net/url"
 )
 
 type (
 	TaskRequestUpdate struct {
-		Name string
+		Name      string
+		rateLimit *api.RateLimitModule
+		logger    *helpers.Logger
 	}
 )
 
@@ -21,12 +24,12 @@ func (t *TaskRequestUpdate) Parse(opt url.Values) error {
 }
 
 func (t TaskRequestUpdate) Run(ctx context.Context) {
-	errLogger := logger.Err.WithField("cron", cronRequestUpdate)
-	outLogger := logger.Out.WithField("cron", cronRequestUpdate)
+	errLogger := t.logger.Err.WithField("cron", cronRequestUpdate)
+	outLogger := t.logger.Out.WithField("cron", cronRequestUpdate)
 
 	outLogger.Println("Updating data...")
 
-	err := api.UpdateRateLimit(ctx)
+	err := t.rateLimit.UpdateAll(ctx)
 
 	if err != nil {
 		errLogger.WithError(err).Errorln("Error on updating request.")
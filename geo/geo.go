@@ -0,0 +1,87 @@
+// Package geo holds the centroid of every country the seed knows, keyed by the
+// ISO 3166-1 alpha-2 code of the country table, for the map formats. The points
+// come from the mledoze/countries dataset, as packaged by pariz/gountries.
+package geo
+
+import (
+	"embed"
+	"encoding/csv"
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+//go:embed centroids.csv
+var files embed.FS
+
+var (
+	loadOnce  sync.Once
+	centroids map[string]Point
+	loadErr   error
+)
+
+// Centroid returns the centroid of the country of code, ignoring case.
+func Centroid(code string) (Point, bool, error) {
+
+	loadOnce.Do(func() {
+		centroids, loadErr = load()
+	})
+
+	if loadErr != nil {
+		return Point{}, false, loadErr
+	}
+
+	point, ok := centroids[strings.ToUpper(code)]
+
+	return point, ok, nil
+}
+
+func load() (map[string]Point, error) {
+
+	file, err := files.Open("centroids.csv")
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = 3
+
+	records, err := reader.ReadAll()
+
+	if err != nil {
+		return nil, errors.Wrap(err, "geo: centroids.csv")
+	}
+
+	points := make(map[string]Point)
+	for i, record := range records {
+
+		if i == 0 {
+			continue
+		}
+
+		latitude, err := strconv.ParseFloat(record[1], 64)
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "geo: centroids.csv line %d", i+1)
+		}
+
+		longitude, err := strconv.ParseFloat(record[2], 64)
+
+		if err != nil {
+			return nil, errors.Wrapf(err, "geo: centroids.csv line %d", i+1)
+		}
+
+		points[record[0]] = Point{Latitude: latitude, Longitude: longitude}
+	}
+
+	return points, nil
+}
@@ -0,0 +1,42 @@
+package geo
+
+import (
+	"corona/seed"
+	"testing"
+)
+
+func TestCentroids(t *testing.T) {
+
+	countries, err := seed.Countries()
+	if err != nil {
+		t.Fatalf("Countries: %v", err)
+	}
+
+	seen := make(map[Point]string)
+	for _, country := range countries {
+
+		point, ok, err := Centroid(country.Alpha2)
+		if err != nil {
+			t.Fatalf("Centroid: %v", err)
+		}
+
+		if !ok {
+			t.Errorf("%s: no centroid", country.Alpha2)
+			continue
+		}
+
+		if point.Latitude < -90 || point.Latitude > 90 || point.Longitude < -180 || point.Longitude > 180 {
+			t.Errorf("%s: %+v is out of range", country.Alpha2, point)
+		}
+
+		// The upstream data once gave neighbours such as KP and KR the same point.
+		if other, ok := seen[point]; ok {
+			t.Errorf("%s and %s share the centroid %+v", other, country.Alpha2, point)
+		}
+		seen[point] = country.Alpha2
+	}
+
+	if _, ok, _ := Centroid("de"); !ok {
+		t.Error("codes should be looked up ignoring case")
+	}
+}
@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 )
@@ -35,3 +36,40 @@ func InitDB(options DBOptions) (*sql.DB, error) {
 	}
 	return db, nil
 }
+
+// Querier runs statements against either a *sql.DB or a *sql.Tx, so models can
+// take part in a transaction.
+type Querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// WithTransaction runs fn in a transaction that is committed when fn succeeds and
+// rolled back when it returns an error or panics.
+func WithTransaction(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) (err error) {
+
+	tx, err := db.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			tx.Rollback()
+		}
+	}()
+
+	err = fn(tx)
+
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
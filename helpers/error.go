@@ -6,6 +6,10 @@ import (
 	"net/http"
 )
 
+// ErrDuplicate is the cause of the errors returned for writes that would break a
+// unique index, e.g. an alias already recorded for its source.
+var ErrDuplicate = errors.New("duplicate")
+
 type (
 	Error struct {
 		Err        error
@@ -18,8 +22,9 @@ func (e *Error) Error() string {
 	return e.Message
 }
 
+// ErrorWrap annotates err with where it happened. It is logged by the handler
+// that returns it, while only message is shown to the client.
 func ErrorWrap(err error, prefix, suffix, message string, status int) *Error {
-	logger.Err.Errorf("error : %v : %v : %v", prefix, suffix, err)
 	return &Error{
 		Err:        errors.Wrapf(err, "%s/%s", prefix, suffix),
 		Message:    message,
@@ -27,6 +32,17 @@ func ErrorWrap(err error, prefix, suffix, message string, status int) *Error {
 	}
 }
 
+// FilterErrorWrap wraps an error of a query built from a Filter: a filter that is
+// invalid is a bad request, anything else an internal error.
+func FilterErrorWrap(err error, prefix, suffix string) *Error {
+
+	if errors.Cause(err) == ErrInvalidFilter {
+		return ErrorWrap(err, prefix, suffix, BadRequestMessage, http.StatusBadRequest)
+	}
+
+	return ErrorWrap(err, prefix, suffix, InternalServerError, http.StatusInternalServerError)
+}
+
 func ErrorResponse(w http.ResponseWriter, message string, status int) {
 	resp := Response{
 		Data: nil,
@@ -36,8 +52,8 @@ func ErrorResponse(w http.ResponseWriter, message string, status int) {
 			},
 		},
 	}
-	w.WriteHeader(status)
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
 	if err := json.NewEncoder(w).Encode(&resp); err != nil {
 		return
 	}
@@ -46,5 +62,7 @@ func ErrorResponse(w http.ResponseWriter, message string, status int) {
 const (
 	InternalServerError   = "Internal Server Error"
 	BadRequestMessage     = "Bad Request"
+	NotFoundMessage       = "Not Found"
 	IncorrectEmailMessage = "Incorrect Email"
+	AliasExistsMessage    = "Alias Already Exists"
 )
@@ -0,0 +1,159 @@
+package helpers
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// Fields is the selection of ?fields=country.name,total_cases and ?embed=none,
+// applied to any response. Paths are dotted JSON names and go through lists,
+// so on a list they select the fields of every row.
+type Fields struct {
+	Paths []string
+	// Compact collapses the nested objects that have an id, e.g. the country of
+	// some figures, into that id.
+	Compact bool
+}
+
+var fieldPath = regexp.MustCompile(`^[a-z0-9_]+(\.[a-z0-9_]+)*$`)
+
+// ParseFields reads the selection of the query.
+func ParseFields(r *http.Request) (Fields, error) {
+
+	var fields Fields
+
+	query := r.URL.Query()
+
+	for _, path := range strings.Split(query.Get("fields"), ",") {
+
+		path = strings.TrimSpace(path)
+
+		if path == "" {
+			continue
+		}
+
+		if !fieldPath.MatchString(path) {
+			return Fields{}, errors.Errorf("fields: malformed path %q", path)
+		}
+
+		fields.Paths = append(fields.Paths, path)
+	}
+
+	switch embed := query.Get("embed"); embed {
+	case "", "all":
+	case "none":
+		fields.Compact = true
+	default:
+		return Fields{}, errors.Errorf("embed: unknown value %q", embed)
+	}
+
+	return fields, nil
+}
+
+// All reports whether the selection keeps everything.
+func (f Fields) All() bool {
+	return len(f.Paths) == 0 && !f.Compact
+}
+
+// Selects reports whether the field at path is kept: either it or one of its
+// parents is selected, or one of its children is, which it has to hold.
+func (f Fields) Selects(path string) bool {
+
+	if len(f.Paths) == 0 {
+		return true
+	}
+
+	for _, selected := range f.Paths {
+		if path == selected || strings.HasPrefix(path, selected+".") || strings.HasPrefix(selected, path+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Collapses reports whether a nested object with an id, found at path, is
+// collapsed into that id: with Compact, unless fields inside it are selected.
+func (f Fields) Collapses(path string) bool {
+
+	if !f.Compact {
+		return false
+	}
+
+	for _, selected := range f.Paths {
+		if strings.HasPrefix(selected, path+".") {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Project returns data as it is encoded to JSON, keeping only the selected
+// fields. Data is returned as is when everything is selected.
+func (f Fields) Project(data interface{}) (interface{}, error) {
+
+	if f.All() {
+		return data, nil
+	}
+
+	raw, err := json.Marshal(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var tree interface{}
+	if err := decoder.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	return f.project(tree, "", false), nil
+}
+
+// project keeps the selected fields of node, found at path. Nested objects are
+// only collapsed when they are the value of a field, not the rows of a list.
+func (f Fields) project(node interface{}, path string, nested bool) interface{} {
+
+	switch value := node.(type) {
+	case []interface{}:
+
+		for i := range value {
+			value[i] = f.project(value[i], path, false)
+		}
+
+		return value
+
+	case map[string]interface{}:
+
+		if id, ok := value["id"]; ok && nested && f.Collapses(path) {
+			return id
+		}
+
+		for key, child := range value {
+
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+
+			if !f.Selects(childPath) {
+				delete(value, key)
+				continue
+			}
+
+			value[key] = f.project(child, childPath, true)
+		}
+
+		return value
+	}
+
+	return node
+}
@@ -0,0 +1,80 @@
+package helpers
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+type (
+	testContinent struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	testCountry struct {
+		Id        string        `json:"id"`
+		Name      string        `json:"name"`
+		Continent testContinent `json:"continent"`
+	}
+
+	testRow struct {
+		Id         string      `json:"id"`
+		Country    testCountry `json:"country"`
+		TotalCases *int64      `json:"total_cases"`
+		NewCases   *int64      `json:"new_cases"`
+		Derived    struct {
+			CasesPerMillion float64 `json:"cases_per_million"`
+		} `json:"derived"`
+	}
+)
+
+func TestFieldsProject(t *testing.T) {
+
+	cases := int64(1800)
+	row := testRow{
+		Id:         "row",
+		Country:    testCountry{Id: "is", Name: "Iceland", Continent: testContinent{Id: "eu", Name: "Europe"}},
+		TotalCases: &cases,
+	}
+	row.Derived.CasesPerMillion = 5000
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"", `[{"id":"row","country":{"id":"is","name":"Iceland","continent":{"id":"eu","name":"Europe"}},` +
+			`"total_cases":1800,"new_cases":null,"derived":{"cases_per_million":5000}}]`},
+		{"fields=country.name,total_cases,new_cases", `[{"country":{"name":"Iceland"},"new_cases":null,"total_cases":1800}]`},
+		{"embed=none", `[{"country":"is","derived":{"cases_per_million":5000},"id":"row","new_cases":null,"total_cases":1800}]`},
+		{"embed=none&fields=country.continent,total_cases", `[{"country":{"continent":"eu"},"total_cases":1800}]`},
+		{"fields=unknown", `[{}]`},
+	}
+
+	for _, test := range tests {
+
+		fields, err := ParseFields(httptest.NewRequest("GET", "/?"+test.query, nil))
+
+		if err != nil {
+			t.Fatalf("%s: %v", test.query, err)
+		}
+
+		projected, err := fields.Project([]testRow{row})
+
+		if err != nil {
+			t.Fatalf("%s: %v", test.query, err)
+		}
+
+		raw, _ := json.Marshal(projected)
+
+		if string(raw) != test.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", test.query, raw, test.want)
+		}
+	}
+
+	for _, query := range []string{"fields=country..name", "fields=name%27--", "embed=some"} {
+		if _, err := ParseFields(httptest.NewRequest("GET", "/?"+query, nil)); err == nil {
+			t.Errorf("%s: expected an error", query)
+		}
+	}
+}
@@ -1,9 +0,0 @@
-package helpers
-
-var (
-	logger *Logger
-)
-
-func Init(log *Logger) {
-	logger = log
-}
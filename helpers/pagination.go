@@ -0,0 +1,153 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/pkg/errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+type (
+	// Cursor points at the last row of a page, by the value it was sorted by
+	// and a key unique to the row, so the next page starts right after it
+	// however many rows come before. Clients only see it encoded.
+	Cursor struct {
+		Sort  string  `json:"s,omitempty"`
+		Dir   string  `json:"d"`
+		Value *string `json:"v"`
+		Key   string  `json:"k"`
+	}
+
+	// Page is a page of a list, with what the response needs to link the
+	// pages around it.
+	Page struct {
+		Data   interface{}
+		Filter Filter
+		Count  int
+		Total  int
+		Cursor string
+	}
+
+	Meta struct {
+		Total      int    `json:"total"`
+		Limit      int    `json:"limit"`
+		Offset     *int   `json:"offset,omitempty"`
+		NextCursor string `json:"next_cursor,omitempty"`
+		Next       string `json:"next,omitempty"`
+		Prev       string `json:"prev,omitempty"`
+	}
+)
+
+// NewCursor returns the encoded cursor of a row sorted as filter asks, whose
+// sort value is value and whose unique key is key.
+func NewCursor(filter Filter, value *string, key string) string {
+
+	cursor := Cursor{Sort: filter.Sort, Dir: sortDir(filter.Dir), Value: value, Key: key}
+
+	raw, _ := json.Marshal(cursor)
+
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor reads a cursor returned by NewCursor.
+func DecodeCursor(value string) (*Cursor, error) {
+
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidFilter, "malformed cursor")
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, errors.Wrap(ErrInvalidFilter, "malformed cursor")
+	}
+
+	return &cursor, nil
+}
+
+// NewPage returns the page of the count rows filter selected out of total.
+func NewPage(data interface{}, filter Filter, count, total int) Page {
+	return Page{Data: data, Filter: filter, Count: count, Total: total}
+}
+
+// More reports whether rows come after the page. Without a cursor the offset
+// tells; with one, a full page is assumed to be followed by more.
+func (p Page) More() bool {
+
+	if p.Filter.Cursor != nil {
+		return p.Count > 0 && p.Count >= p.Filter.Limit
+	}
+
+	return p.Filter.Offset+p.Count < p.Total
+}
+
+// Meta returns the meta block of the page, linking the next and previous pages
+// relative to u, the URL it was requested at.
+func (p Page) Meta(u *url.URL) *Meta {
+
+	meta := &Meta{
+		Total:      p.Total,
+		Limit:      p.Filter.Limit,
+		NextCursor: p.Cursor,
+	}
+
+	if p.Filter.Cursor != nil {
+
+		if p.Cursor != "" {
+			meta.Next = link(u, map[string]string{"cursor": p.Cursor, "offset": ""})
+		}
+
+		return meta
+	}
+
+	offset := p.Filter.Offset
+	meta.Offset = &offset
+
+	if p.More() {
+		meta.Next = link(u, map[string]string{"offset": strconv.Itoa(offset + p.Filter.Limit)})
+	}
+
+	if offset > 0 {
+
+		prev := offset - p.Filter.Limit
+		if prev < 0 {
+			prev = 0
+		}
+
+		meta.Prev = link(u, map[string]string{"offset": strconv.Itoa(prev)})
+	}
+
+	return meta
+}
+
+// link returns u with the query values of set replaced, removing those set to "".
+func link(u *url.URL, set map[string]string) string {
+
+	query := u.Query()
+	for key, value := range set {
+		if value == "" {
+			query.Del(key)
+			continue
+		}
+		query.Set(key, value)
+	}
+
+	return u.Path + "?" + query.Encode()
+}
+
+func sortDir(dir string) string {
+
+	if strings.EqualFold(dir, "desc") {
+		return "DESC"
+	}
+
+	return "ASC"
+}
@@ -0,0 +1,238 @@
+package helpers
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// ErrInvalidFilter is the cause of the errors returned for filters that cannot
+// be turned into a query, e.g. a sort on a column that is not whitelisted.
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// QueryBuilder turns a Filter into the WHERE, ORDER BY and LIMIT clauses of a
+// list query. Values are always bound as parameters and columns are looked up
+// in whitelists, so nothing the client sends is written into the SQL.
+type QueryBuilder struct {
+	filter Filter
+	where  []string
+	column string
+	dir    string
+	key    string
+	args   []interface{}
+	err    error
+}
+
+func NewQueryBuilder(filter Filter) *QueryBuilder {
+	return &QueryBuilder{filter: filter}
+}
+
+// Where adds a condition, joined to the others with AND. Each ? in condition is
+// bound to the next of args.
+func (q *QueryBuilder) Where(condition string, args ...interface{}) {
+
+	var (
+		clause strings.Builder
+		next   int
+	)
+	for _, r := range condition {
+
+		if r != '?' {
+			clause.WriteRune(r)
+			continue
+		}
+
+		if next == len(args) {
+			q.fail(errors.Errorf("missing argument for %q", condition))
+			return
+		}
+
+		clause.WriteString(q.bind(args[next]))
+		next++
+	}
+
+	if next != len(args) {
+		q.fail(errors.Errorf("too many arguments for %q", condition))
+		return
+	}
+
+	q.where = append(q.where, clause.String())
+}
+
+// Search keeps the rows where one of columns contains the search of the filter,
+// ignoring case. It does nothing when there is no search.
+func (q *QueryBuilder) Search(columns ...string) {
+
+	if q.filter.Search == "" || len(columns) == 0 {
+		return
+	}
+
+	pattern := "%" + escapeLike(q.filter.Search) + "%"
+
+	var conditions []string
+	for _, column := range columns {
+		conditions = append(conditions, fmt.Sprintf("LOWER(%s) LIKE LOWER(%s)", column, q.bind(pattern)))
+	}
+
+	q.where = append(q.where, "("+strings.Join(conditions, " OR ")+")")
+}
+
+// Range bounds the rows by the ranges of the filter, on the expressions columns
+// maps their fields to. A field missing from columns is an ErrInvalidFilter.
+func (q *QueryBuilder) Range(columns map[string]string) {
+
+	for _, r := range q.filter.Ranges {
+
+		column, ok := columns[r.Field]
+
+		if !ok {
+			q.fail(errors.Wrapf(ErrInvalidFilter, "unknown filter %q", r.Field+"_"+r.Op))
+			return
+		}
+
+		op := ">="
+		if r.Op == RangeLte {
+			op = "<="
+		}
+
+		q.where = append(q.where, fmt.Sprintf("%s %s %s::float8", column, op, q.bind(r.Value)))
+	}
+}
+
+// OrderBy sorts by the expression columns maps the sort of the filter to, or the
+// one of fallback without a sort, in the direction of the filter. Unknown values
+// come last and ties are broken by key, a column unique to the rows, which is
+// also what a cursor seeks on. A sort missing from columns is an
+// ErrInvalidFilter.
+func (q *QueryBuilder) OrderBy(columns map[string]string, fallback string, key string) {
+
+	sort := q.filter.Sort
+	if sort == "" {
+		sort = fallback
+	}
+
+	column, ok := columns[sort]
+
+	if !ok {
+		q.fail(errors.Wrapf(ErrInvalidFilter, "unknown sort %q", sort))
+		return
+	}
+
+	q.column, q.dir, q.key = column, sortDir(q.filter.Dir), key
+}
+
+// Build returns the clauses, ending with the limit and offset of the filter, and
+// the arguments they are bound to. With a cursor the rows start after the one it
+// points at rather than at the offset.
+func (q *QueryBuilder) Build() (string, []interface{}, error) {
+
+	if q.err != nil {
+		return "", nil, q.err
+	}
+
+	limit, offset := q.filter.Limit, q.filter.Offset
+	if limit < 0 {
+		limit = 0
+	}
+	if offset < 0 || q.filter.Cursor != nil {
+		offset = 0
+	}
+
+	args := append([]interface{}{}, q.args...)
+	where := append([]string{}, q.where...)
+
+	if q.filter.Cursor != nil {
+
+		seek, err := q.seek(*q.filter.Cursor, &args)
+
+		if err != nil {
+			return "", nil, err
+		}
+
+		where = append(where, seek)
+	}
+
+	var clauses []string
+
+	if len(where) > 0 {
+		clauses = append(clauses, "WHERE "+strings.Join(where, " AND "))
+	}
+
+	if q.column != "" {
+
+		order := fmt.Sprintf("%s %s NULLS LAST", q.column, q.dir)
+		if q.key != "" {
+			order += fmt.Sprintf(", %s ASC", q.key)
+		}
+
+		clauses = append(clauses, "ORDER BY "+order)
+	}
+
+	clauses = append(clauses, fmt.Sprintf("LIMIT $%d OFFSET $%d", len(args)+1, len(args)+2))
+	args = append(args, limit, offset)
+
+	return strings.Join(clauses, "\n\t\t"), args, nil
+}
+
+// Count returns the WHERE clause, if any, counting every row of the filter
+// whatever its page, and the arguments it is bound to.
+func (q *QueryBuilder) Count() (string, []interface{}, error) {
+
+	if q.err != nil {
+		return "", nil, q.err
+	}
+
+	if len(q.where) == 0 {
+		return "", nil, nil
+	}
+
+	return "WHERE " + strings.Join(q.where, " AND "), append([]interface{}{}, q.args...), nil
+}
+
+// seek returns the condition keeping the rows sorted after the one cursor points
+// at, binding its values to args. Values are bound as text and read by Postgres
+// as the type of the column they are compared to.
+func (q *QueryBuilder) seek(cursor Cursor, args *[]interface{}) (string, error) {
+
+	if q.column == "" || q.key == "" {
+		return "", errors.Wrap(ErrInvalidFilter, "the list cannot be paged with a cursor")
+	}
+
+	if cursor.Sort != q.filter.Sort || cursor.Dir != q.dir {
+		return "", errors.Wrap(ErrInvalidFilter, "the cursor was made for another sort")
+	}
+
+	bind := func(arg interface{}) string {
+		*args = append(*args, arg)
+		return fmt.Sprintf("$%d", len(*args))
+	}
+
+	if cursor.Value == nil {
+		return fmt.Sprintf("((%s) IS NULL AND %s > %s)", q.column, q.key, bind(cursor.Key)), nil
+	}
+
+	op := ">"
+	if q.dir == "DESC" {
+		op = "<"
+	}
+
+	return fmt.Sprintf("((%s) %s %s OR ((%s) = %s AND %s > %s) OR (%s) IS NULL)",
+		q.column, op, bind(*cursor.Value), q.column, bind(*cursor.Value), q.key, bind(cursor.Key), q.column), nil
+}
+
+// bind adds arg to the arguments and returns its placeholder.
+func (q *QueryBuilder) bind(arg interface{}) string {
+	q.args = append(q.args, arg)
+	return fmt.Sprintf("$%d", len(q.args))
+}
+
+func (q *QueryBuilder) fail(err error) {
+	if q.err == nil {
+		q.err = err
+	}
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern so value is matched as is.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
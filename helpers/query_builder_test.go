@@ -0,0 +1,158 @@
+package helpers
+
+import (
+	"github.com/pkg/errors"
+	"reflect"
+	"testing"
+)
+
+var testSortColumns = map[string]string{
+	"name":        "c.name",
+	"total_cases": "cd.total_cases",
+}
+
+func TestQueryBuilder(t *testing.T) {
+
+	filter := Filter{
+		FilterOption: FilterOption{Limit: 10, Offset: 20, Search: "'; DROP TABLE country; --%", Dir: "desc",
+			Sort: "total_cases"},
+	}
+
+	builder := NewQueryBuilder(filter)
+	builder.Where("c.continent_id = ?", "europe")
+	builder.Search("c.name", "c.code")
+	builder.OrderBy(testSortColumns, "name", "c.name")
+
+	clauses, args, err := builder.Build()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "WHERE c.continent_id = $1 AND (LOWER(c.name) LIKE LOWER($2) OR LOWER(c.code) LIKE LOWER($3))\n\t\t" +
+		"ORDER BY cd.total_cases DESC NULLS LAST, c.name ASC\n\t\t" +
+		"LIMIT $4 OFFSET $5"
+	if clauses != want {
+		t.Errorf("got clauses\n%s\nwant\n%s", clauses, want)
+	}
+
+	pattern := `%'; DROP TABLE country; --\%%`
+	wantArgs := []interface{}{"europe", pattern, pattern, 10, 20}
+	if !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("got args %v, want %v", args, wantArgs)
+	}
+}
+
+func TestQueryBuilderDefaults(t *testing.T) {
+
+	builder := NewQueryBuilder(Filter{FilterOption: FilterOption{Limit: -1, Dir: "; DROP TABLE country"}})
+	builder.Search("c.name")
+	builder.OrderBy(testSortColumns, "name", "")
+
+	clauses, args, err := builder.Build()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "ORDER BY c.name ASC NULLS LAST\n\t\tLIMIT $1 OFFSET $2"; clauses != want {
+		t.Errorf("got clauses %q, want %q", clauses, want)
+	}
+
+	if !reflect.DeepEqual(args, []interface{}{0, 0}) {
+		t.Errorf("got args %v, want [0 0]", args)
+	}
+}
+
+func TestQueryBuilderErrors(t *testing.T) {
+
+	builder := NewQueryBuilder(Filter{FilterOption: FilterOption{Sort: "password"}})
+	builder.OrderBy(testSortColumns, "name", "")
+
+	if _, _, err := builder.Build(); errors.Cause(err) != ErrInvalidFilter {
+		t.Errorf("got %v for an unknown sort, want %v", err, ErrInvalidFilter)
+	}
+
+	builder = NewQueryBuilder(Filter{})
+	builder.Where("c.id = ? AND c.code = ?", 1)
+
+	if _, _, err := builder.Build(); err == nil {
+		t.Error("expected an error for a missing argument")
+	}
+
+	builder = NewQueryBuilder(Filter{Ranges: []Range{{Field: "password", Op: RangeGte, Value: 1}}})
+	builder.Range(testSortColumns)
+
+	if _, _, err := builder.Build(); errors.Cause(err) != ErrInvalidFilter {
+		t.Errorf("got %v for an unknown range, want %v", err, ErrInvalidFilter)
+	}
+}
+
+func TestQueryBuilderRange(t *testing.T) {
+
+	builder := NewQueryBuilder(Filter{Ranges: []Range{
+		{Field: "total_cases", Op: RangeGte, Value: 1000},
+		{Field: "total_cases", Op: RangeLte, Value: 5000},
+	}})
+	builder.Range(testSortColumns)
+
+	clauses, args, err := builder.Build()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "WHERE cd.total_cases >= $1::float8 AND cd.total_cases <= $2::float8\n\t\tLIMIT $3 OFFSET $4"
+	if clauses != want {
+		t.Errorf("got clauses %q, want %q", clauses, want)
+	}
+
+	if !reflect.DeepEqual(args, []interface{}{1000.0, 5000.0, 0, 0}) {
+		t.Errorf("got args %v, want [1000 5000 0 0]", args)
+	}
+}
+
+func TestQueryBuilderCursor(t *testing.T) {
+
+	value := "8000"
+	filter := Filter{
+		FilterOption: FilterOption{Limit: 10, Offset: 30, Sort: "total_cases", Dir: "desc"},
+		Cursor:       &Cursor{Sort: "total_cases", Dir: "DESC", Value: &value, Key: "Germany"},
+	}
+
+	builder := NewQueryBuilder(filter)
+	builder.Where("c.continent_id = ?", "europe")
+	builder.OrderBy(testSortColumns, "name", "c.name")
+
+	clauses, args, err := builder.Build()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "WHERE c.continent_id = $1 AND " +
+		"((cd.total_cases) < $2 OR ((cd.total_cases) = $3 AND c.name > $4) OR (cd.total_cases) IS NULL)\n\t\t" +
+		"ORDER BY cd.total_cases DESC NULLS LAST, c.name ASC\n\t\t" +
+		"LIMIT $5 OFFSET $6"
+	if clauses != want {
+		t.Errorf("got clauses\n%s\nwant\n%s", clauses, want)
+	}
+
+	if wantArgs := []interface{}{"europe", "8000", "8000", "Germany", 10, 0}; !reflect.DeepEqual(args, wantArgs) {
+		t.Errorf("got args %v, want %v", args, wantArgs)
+	}
+
+	count, countArgs, err := builder.Count()
+
+	if err != nil || count != "WHERE c.continent_id = $1" || !reflect.DeepEqual(countArgs, []interface{}{"europe"}) {
+		t.Errorf("got count %q %v %v", count, countArgs, err)
+	}
+
+	filter.Sort = "name"
+	builder = NewQueryBuilder(filter)
+	builder.OrderBy(testSortColumns, "name", "c.name")
+
+	if _, _, err := builder.Build(); errors.Cause(err) != ErrInvalidFilter {
+		t.Errorf("got %v for a cursor of another sort, want %v", err, ErrInvalidFilter)
+	}
+}
@@ -5,15 +5,22 @@ import (
 	"encoding/json"
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/schema"
+	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"html"
 	"net/http"
 	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
-var decoder = schema.NewDecoder()
-var validate *validator.Validate
+// DateLayout is the layout of the dates requests send and responses show.
+const DateLayout = "2006-01-02"
+
+var decoder = newDecoder()
+var validate = validator.New()
 
 type (
 	FilterOption struct {
@@ -21,14 +28,37 @@ type (
 		Offset int    `json:"offset" schema:"offset"`
 		Search string `json:"search" schema:"search"`
 		Dir    string `json:"dir" schema:"dir"`
+		Sort   string `json:"sort" schema:"sort"`
+	}
+
+	// Range bounds a field of the listed rows, e.g. total_cases_gte=1000.
+	Range struct {
+		Field string  `json:"field"`
+		Op    string  `json:"op"`
+		Value float64 `json:"value"`
 	}
 
 	Filter struct {
 		FilterOption `json:"filter,omitempty"`
 		ContinentId  uuid.UUID `json:"continent_id,omitempty"`
+		Continent    string    `json:"continent,omitempty" schema:"continent"`
+		UpdatedSince time.Time `json:"updated_since,omitempty" schema:"-"`
+		Ranges       []Range   `json:"ranges,omitempty" schema:"-"`
+		Cursor       *Cursor   `json:"cursor,omitempty" schema:"-"`
 	}
 )
 
+const (
+	RangeGte = "gte"
+	RangeLte = "lte"
+)
+
+func newDecoder() *schema.Decoder {
+	decoder := schema.NewDecoder()
+	decoder.IgnoreUnknownKeys(true)
+	return decoder
+}
+
 func ParseBodyRequestData(ctx context.Context, r *http.Request, data interface{}) error {
 
 	err := json.NewDecoder(r.Body).Decode(&data)
@@ -46,7 +76,6 @@ func ParseBodyRequestData(ctx context.Context, r *http.Request, data interface{}
 		field.SetString(html.EscapeString(str))
 
 	}
-	validate = validator.New()
 	err = validate.Struct(data)
 
 	if err != nil {
@@ -57,20 +86,89 @@ func ParseBodyRequestData(ctx context.Context, r *http.Request, data interface{}
 
 }
 
+// ParseFilter reads the filter of a list from the query. Besides the paging
+// options, whose limit defaults to DefaultLimit and is capped at MaxLimit, it
+// reads the cursor of the previous page, the bounds given as <field>_gte and
+// <field>_lte, and updated_since as a date or an RFC 3339 time. Which fields can
+// be bounded is up to the list.
 func ParseFilter(ctx context.Context, r *http.Request) (Filter, error) {
 
-	//marshal, _ := json.Marshal(r.URL.Query())
-	//fmt.Println(string(marshal))
+	query := r.URL.Query()
 
 	var filter Filter
-	err := decoder.Decode(&filter, r.URL.Query())
+	err := decoder.Decode(&filter, query)
 	if err != nil {
-		return filter, nil
+		return Filter{}, err
 	}
 
 	if strings.ToLower(filter.Dir) != "asc" && strings.ToLower(filter.Dir) != "desc" {
 		filter.Dir = "ASC"
 	}
 
+	if filter.Limit <= 0 {
+		filter.Limit = DefaultLimit
+	}
+	if filter.Limit > MaxLimit {
+		filter.Limit = MaxLimit
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	if value := query.Get("cursor"); value != "" {
+		filter.Cursor, err = DecodeCursor(value)
+		if err != nil {
+			return Filter{}, err
+		}
+		filter.Offset = 0
+	}
+
+	if value := query.Get("updated_since"); value != "" {
+		filter.UpdatedSince, err = parseTime(value)
+		if err != nil {
+			return Filter{}, errors.Wrap(err, "updated_since")
+		}
+	}
+
+	var keys []string
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+
+		var op string
+		switch {
+		case strings.HasSuffix(key, "_"+RangeGte):
+			op = RangeGte
+		case strings.HasSuffix(key, "_"+RangeLte):
+			op = RangeLte
+		default:
+			continue
+		}
+
+		value, err := strconv.ParseFloat(query.Get(key), 64)
+		if err != nil {
+			return Filter{}, errors.Wrap(err, key)
+		}
+
+		filter.Ranges = append(filter.Ranges, Range{
+			Field: strings.TrimSuffix(key, "_"+op),
+			Op:    op,
+			Value: value,
+		})
+	}
+
 	return filter, nil
 }
+
+// parseTime reads a date, as 2006-01-02, or an RFC 3339 time.
+func parseTime(value string) (time.Time, error) {
+
+	if t, err := time.Parse(DateLayout, value); err == nil {
+		return t, nil
+	}
+
+	return time.Parse(time.RFC3339, value)
+}
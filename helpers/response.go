@@ -4,8 +4,17 @@ type (
 	Response struct {
 		BaseResponse
 		Data interface{} `json:"data"`
+		Meta *Meta       `json:"meta,omitempty"`
+		// Fields is the selection the data is encoded with.
+		Fields Fields `json:"-"`
 	}
 	BaseResponse struct {
 		Errors []string `json:"errors,omitempty"`
 	}
+
+	// Table is data whose rows are not the data itself, for the formats that
+	// only hold flat rows, such as CSV.
+	Table interface {
+		Rows() interface{}
+	}
 )
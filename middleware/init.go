@@ -2,15 +2,20 @@ package middleware
 
 import (
 	"corona/helpers"
-	"database/sql"
+	"corona/storage"
 )
 
-var (
-	dbPool *sql.DB
-	logger *helpers.Logger
+type (
+	// Middleware checks the tokens and rate limits of requests against its store.
+	Middleware struct {
+		store  storage.Store
+		logger *helpers.Logger
+	}
 )
 
-func Init(db *sql.DB, log *helpers.Logger) {
-	dbPool = db
-	logger = log
+func NewMiddleware(store storage.Store, logger *helpers.Logger) *Middleware {
+	return &Middleware{
+		store:  store,
+		logger: logger,
+	}
 }
@@ -12,14 +12,14 @@ import (
 	"time"
 )
 
-func TokenMiddleware(next http.Handler) http.Handler {
+func (m *Middleware) TokenMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
 		ctx := r.Context()
 
 		tokenKey := r.Header.Get("Token")
 
-		token, err := tokenValidation(ctx, tokenKey)
+		token, err := m.tokenValidation(ctx, tokenKey)
 
 		if err != nil {
 			helpers.ErrorResponse(w, err.Error(), http.StatusUnauthorized)
@@ -34,14 +34,14 @@ func TokenMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func RateLimitMiddleware(next http.Handler) http.Handler {
+func (m *Middleware) RateLimitMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
 		ctx := r.Context()
 
 		tokenKey := r.Header.Get("Token")
 
-		token, err := rateLimitValidation(ctx, tokenKey)
+		token, err := m.rateLimitValidation(ctx, tokenKey)
 
 		if err != nil {
 			helpers.ErrorResponse(w, err.Error(), http.StatusUnauthorized)
@@ -56,11 +56,11 @@ func RateLimitMiddleware(next http.Handler) http.Handler {
 	})
 }
 
-func tokenValidation(ctx context.Context, token string) (models.TokenModel, error) {
+func (m *Middleware) tokenValidation(ctx context.Context, token string) (models.TokenModel, error) {
 
 	encToken := base64.StdEncoding.EncodeToString([]byte(token))
 
-	tokenData, err := models.GetOneTokenByTokenKey(ctx, dbPool, encToken)
+	tokenData, err := m.store.Token.GetOneByTokenKey(ctx, encToken)
 	if err != nil {
 
 		if err == sql.ErrNoRows {
@@ -80,11 +80,11 @@ func tokenValidation(ctx context.Context, token string) (models.TokenModel, erro
 	return tokenData, nil
 }
 
-func rateLimitValidation(ctx context.Context, token string) (models.TokenModel, error) {
+func (m *Middleware) rateLimitValidation(ctx context.Context, token string) (models.TokenModel, error) {
 
 	encToken := base64.StdEncoding.EncodeToString([]byte(token))
 
-	tokenData, err := models.GetOneTokenByTokenKey(ctx, dbPool, encToken)
+	tokenData, err := m.store.Token.GetOneByTokenKey(ctx, encToken)
 	if err != nil {
 
 		if err == sql.ErrNoRows {
@@ -93,19 +93,15 @@ func rateLimitValidation(ctx context.Context, token string) (models.TokenModel,
 		return models.TokenModel{}, err
 	}
 
-	user, err := models.GetOneUser(ctx, dbPool, tokenData.UserId)
+	user, err := m.store.User.GetOne(ctx, tokenData.UserId)
 
 	if err != nil {
 		return models.TokenModel{}, errors.Wrap(err, "No user")
 	}
 
-	subscription, err := models.GetOneSubscription(ctx, dbPool, user.SubscriptionId)
+	subscription := user.Subscription
 
-	if err != nil {
-		return models.TokenModel{}, errors.Wrap(err, "No subscription")
-	}
-
-	rateLimit, err := models.GetOneRateLimitByUserId(ctx, dbPool, tokenData.UserId)
+	rateLimit, err := m.store.RateLimit.GetOneByUserId(ctx, tokenData.UserId)
 
 	if err != nil {
 		return models.TokenModel{}, errors.Wrap(err, "No rate limit")
@@ -122,7 +118,7 @@ func rateLimitValidation(ctx context.Context, token string) (models.TokenModel,
 		Valid: true,
 	}
 
-	err = rateLimit.Update(ctx, dbPool)
+	err = m.store.RateLimit.Update(ctx, &rateLimit)
 
 	if err != nil {
 		return models.TokenModel{}, errors.Wrap(err, "Update Failed")
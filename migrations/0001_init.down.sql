@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS rate_limit;
+DROP TABLE IF EXISTS token;
+DROP TABLE IF EXISTS "user";
+DROP TABLE IF EXISTS subscription;
+DROP TABLE IF EXISTS corona_data;
+DROP TABLE IF EXISTS country;
+DROP TABLE IF EXISTS continent;
@@ -0,0 +1,87 @@
+CREATE EXTENSION IF NOT EXISTS pgcrypto;
+
+CREATE TABLE IF NOT EXISTS continent (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name       TEXT        NOT NULL,
+    code       TEXT        NOT NULL,
+    created_by UUID        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_by UUID,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS country (
+    id           UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    continent_id UUID        NOT NULL REFERENCES continent (id),
+    name         TEXT        NOT NULL,
+    code         TEXT        NOT NULL,
+    created_by   UUID        NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_by   UUID,
+    updated_at   TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS corona_data (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    country_id      UUID        NOT NULL REFERENCES country (id),
+    total_cases     TEXT        NOT NULL,
+    new_cases       TEXT        NOT NULL,
+    total_deaths    TEXT        NOT NULL,
+    new_deaths      TEXT        NOT NULL,
+    total_recovered TEXT        NOT NULL,
+    active_cases    TEXT        NOT NULL,
+    serious_cases   TEXT        NOT NULL,
+    total_tests     TEXT        NOT NULL,
+    population      TEXT        NOT NULL,
+    created_by      UUID        NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_by      UUID,
+    updated_at      TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS subscription (
+    id                UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_type TEXT        NOT NULL,
+    request_per_day   INTEGER     NOT NULL,
+    is_delete         BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_by        UUID        NOT NULL,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_by        UUID,
+    updated_at        TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS "user" (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    subscription_id UUID        NOT NULL REFERENCES subscription (id),
+    name            TEXT        NOT NULL,
+    email           TEXT        NOT NULL UNIQUE,
+    password        TEXT        NOT NULL,
+    is_active       BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_by      UUID        NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_by      UUID,
+    updated_at      TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS token (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id    UUID        NOT NULL REFERENCES "user" (id),
+    token_key  TEXT        NOT NULL UNIQUE,
+    expired_at TIMESTAMPTZ NOT NULL,
+    is_active  BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_by UUID        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_by UUID,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS rate_limit (
+    id            UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id       UUID        NOT NULL REFERENCES "user" (id),
+    total_request INTEGER     NOT NULL DEFAULT 0,
+    is_delete     BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_by    UUID        NOT NULL,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_by    UUID,
+    updated_at    TIMESTAMPTZ
+);
@@ -0,0 +1,26 @@
+CREATE TABLE country_alias (
+    id         UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    country_id UUID        NOT NULL REFERENCES country (id) ON DELETE CASCADE,
+    alias      TEXT        NOT NULL,
+    source     TEXT        NOT NULL DEFAULT '',
+    created_by UUID        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_by UUID,
+    updated_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX country_alias_alias_source_idx ON country_alias (LOWER(alias), source);
+CREATE INDEX country_alias_country_id_idx ON country_alias (country_id);
+
+-- Names worldometers publishes that used to be fixed up in the scrapper.
+INSERT INTO country_alias (country_id, alias, source, created_by)
+SELECT c.id, a.alias, 'worldometers', gen_random_uuid()
+FROM (VALUES ('Czechia', 'Czech Republic'),
+             ('DRC', 'DR Congo'),
+             ('Ivory Coast', 'Cote D''Ivoire'),
+             ('CAR', 'Central African Republic'),
+             ('UAE', 'United Arab Emirates'),
+             ('UK', 'United Kingdom'),
+             ('USA', 'United States'),
+             ('S.Korea', 'South Korea')) AS a (alias, name)
+INNER JOIN country c ON LOWER(c.name) = LOWER(a.name);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS unmatched_scrape_row;
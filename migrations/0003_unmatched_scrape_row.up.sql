@@ -0,0 +1,18 @@
+CREATE TABLE unmatched_scrape_row (
+    id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    source      TEXT        NOT NULL,
+    name        TEXT        NOT NULL,
+    cells       TEXT[]      NOT NULL,
+    scraped_at  TIMESTAMPTZ NOT NULL,
+    country_id  UUID REFERENCES country (id) ON DELETE SET NULL,
+    resolved_by UUID,
+    resolved_at TIMESTAMPTZ,
+    created_by  UUID        NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_by  UUID,
+    updated_at  TIMESTAMPTZ
+);
+
+-- A name stays in the queue once until it is resolved, later scrapes refresh it.
+CREATE UNIQUE INDEX unmatched_scrape_row_pending_idx ON unmatched_scrape_row (source, LOWER(name))
+    WHERE resolved_at IS NULL;
@@ -0,0 +1,19 @@
+ALTER TABLE corona_data
+    ALTER COLUMN total_cases TYPE TEXT USING COALESCE(total_cases::TEXT, ''),
+    ALTER COLUMN total_cases SET NOT NULL,
+    ALTER COLUMN new_cases TYPE TEXT USING COALESCE(new_cases::TEXT, ''),
+    ALTER COLUMN new_cases SET NOT NULL,
+    ALTER COLUMN total_deaths TYPE TEXT USING COALESCE(total_deaths::TEXT, ''),
+    ALTER COLUMN total_deaths SET NOT NULL,
+    ALTER COLUMN new_deaths TYPE TEXT USING COALESCE(new_deaths::TEXT, ''),
+    ALTER COLUMN new_deaths SET NOT NULL,
+    ALTER COLUMN total_recovered TYPE TEXT USING COALESCE(total_recovered::TEXT, ''),
+    ALTER COLUMN total_recovered SET NOT NULL,
+    ALTER COLUMN active_cases TYPE TEXT USING COALESCE(active_cases::TEXT, ''),
+    ALTER COLUMN active_cases SET NOT NULL,
+    ALTER COLUMN serious_cases TYPE TEXT USING COALESCE(serious_cases::TEXT, ''),
+    ALTER COLUMN serious_cases SET NOT NULL,
+    ALTER COLUMN total_tests TYPE TEXT USING COALESCE(total_tests::TEXT, ''),
+    ALTER COLUMN total_tests SET NOT NULL,
+    ALTER COLUMN population TYPE TEXT USING COALESCE(population::TEXT, ''),
+    ALTER COLUMN population SET NOT NULL;
@@ -0,0 +1,21 @@
+-- Figures used to be stored as display strings ( "+1,234", "N/A" ), now they are
+-- numbers with NULL meaning unknown.
+ALTER TABLE corona_data
+    ALTER COLUMN total_cases TYPE BIGINT USING NULLIF(regexp_replace(total_cases, '[^0-9-]', '', 'g'), '')::BIGINT,
+    ALTER COLUMN total_cases DROP NOT NULL,
+    ALTER COLUMN new_cases TYPE BIGINT USING NULLIF(regexp_replace(new_cases, '[^0-9-]', '', 'g'), '')::BIGINT,
+    ALTER COLUMN new_cases DROP NOT NULL,
+    ALTER COLUMN total_deaths TYPE BIGINT USING NULLIF(regexp_replace(total_deaths, '[^0-9-]', '', 'g'), '')::BIGINT,
+    ALTER COLUMN total_deaths DROP NOT NULL,
+    ALTER COLUMN new_deaths TYPE BIGINT USING NULLIF(regexp_replace(new_deaths, '[^0-9-]', '', 'g'), '')::BIGINT,
+    ALTER COLUMN new_deaths DROP NOT NULL,
+    ALTER COLUMN total_recovered TYPE BIGINT USING NULLIF(regexp_replace(total_recovered, '[^0-9-]', '', 'g'), '')::BIGINT,
+    ALTER COLUMN total_recovered DROP NOT NULL,
+    ALTER COLUMN active_cases TYPE BIGINT USING NULLIF(regexp_replace(active_cases, '[^0-9-]', '', 'g'), '')::BIGINT,
+    ALTER COLUMN active_cases DROP NOT NULL,
+    ALTER COLUMN serious_cases TYPE BIGINT USING NULLIF(regexp_replace(serious_cases, '[^0-9-]', '', 'g'), '')::BIGINT,
+    ALTER COLUMN serious_cases DROP NOT NULL,
+    ALTER COLUMN total_tests TYPE BIGINT USING NULLIF(regexp_replace(total_tests, '[^0-9-]', '', 'g'), '')::BIGINT,
+    ALTER COLUMN total_tests DROP NOT NULL,
+    ALTER COLUMN population TYPE BIGINT USING NULLIF(regexp_replace(population, '[^0-9-]', '', 'g'), '')::BIGINT,
+    ALTER COLUMN population DROP NOT NULL;
@@ -0,0 +1,19 @@
+CREATE TABLE corona_snapshot (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    country_id      UUID        NOT NULL REFERENCES country (id),
+    report_date     DATE        NOT NULL,
+    total_cases     BIGINT,
+    new_cases       BIGINT,
+    total_deaths    BIGINT,
+    new_deaths      BIGINT,
+    total_recovered BIGINT,
+    active_cases    BIGINT,
+    serious_cases   BIGINT,
+    total_tests     BIGINT,
+    population      BIGINT,
+    created_by      UUID        NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_by      UUID,
+    updated_at      TIMESTAMPTZ,
+    UNIQUE (country_id, report_date)
+);
@@ -0,0 +1,13 @@
+CREATE TABLE world_summary (
+    id              UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    report_date     DATE        NOT NULL UNIQUE,
+    total_cases     BIGINT,
+    total_deaths    BIGINT,
+    total_recovered BIGINT,
+    source          TEXT        NOT NULL,
+    is_estimated    BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_by      UUID        NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_by      UUID,
+    updated_at      TIMESTAMPTZ
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS country_code_idx;
+DROP INDEX IF EXISTS continent_code_idx;
+
+ALTER TABLE country DROP COLUMN IF EXISTS numeric_code;
+ALTER TABLE country DROP COLUMN IF EXISTS alpha3;
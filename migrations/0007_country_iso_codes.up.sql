@@ -0,0 +1,9 @@
+ALTER TABLE country ADD COLUMN alpha3 TEXT NOT NULL DEFAULT '';
+ALTER TABLE country ADD COLUMN numeric_code TEXT NOT NULL DEFAULT '';
+
+-- The seed command keys continents and countries on their code.
+UPDATE continent SET code = UPPER(code);
+UPDATE country SET code = UPPER(code);
+
+CREATE UNIQUE INDEX continent_code_idx ON continent (code);
+CREATE UNIQUE INDEX country_code_idx ON country (code);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS corona_data_country_id_idx;
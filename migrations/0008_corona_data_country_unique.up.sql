@@ -0,0 +1,8 @@
+-- Keep the most recent row of every country before corona_data is upserted on
+-- country_id.
+DELETE FROM corona_data a
+USING corona_data b
+WHERE a.country_id = b.country_id
+  AND (COALESCE(a.updated_at, a.created_at), a.id) < (COALESCE(b.updated_at, b.created_at), b.id);
+
+CREATE UNIQUE INDEX corona_data_country_id_idx ON corona_data (country_id);
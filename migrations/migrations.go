@@ -0,0 +1,300 @@
+// Package migrations holds the versioned database schema. Every change is a pair
+// of NNNN_name.up.sql / NNNN_name.down.sql files embedded in the binary, applied
+// in version order and recorded in the schema_migrations table.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"github.com/pkg/errors"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+type (
+	Migration struct {
+		Version int
+		Name    string
+		Up      string
+		Down    string
+	}
+
+	Status struct {
+		Migration
+		AppliedAt *time.Time
+	}
+)
+
+// lockKey serializes migration runs across processes through pg_advisory_lock.
+const lockKey = 7243001
+
+//go:embed *.sql
+var files embed.FS
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Load returns the embedded migrations ordered by version.
+func Load() ([]Migration, error) {
+
+	entries, err := files.ReadDir(".")
+
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+
+	for _, entry := range entries {
+
+		match := fileName.FindStringSubmatch(entry.Name())
+
+		if match == nil {
+			return nil, errors.Errorf("migrations: unexpected file name %s", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+
+		content, err := files.ReadFile(path.Join(".", entry.Name()))
+
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+
+		if migration.Name != match[2] {
+			return nil, errors.Errorf("migrations: version %d used by %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	var migrations []Migration
+	for _, migration := range byVersion {
+
+		if migration.Up == "" || migration.Down == "" {
+			return nil, errors.Errorf("migrations: %04d_%s needs both an up and a down file",
+				migration.Version, migration.Name)
+		}
+
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Up applies every pending migration, each in its own transaction, and returns
+// the ones applied.
+func Up(ctx context.Context, db *sql.DB) ([]Migration, error) {
+
+	var applied []Migration
+
+	err := withLock(ctx, db, func(conn *sql.Conn) error {
+
+		statuses, err := status(ctx, conn)
+
+		if err != nil {
+			return err
+		}
+
+		for _, s := range statuses {
+
+			if s.AppliedAt != nil {
+				continue
+			}
+
+			err := run(ctx, conn, s.Migration, s.Up,
+				`INSERT INTO schema_migrations(version, name, applied_at) VALUES($1, $2, now())`,
+				s.Version, s.Name)
+
+			if err != nil {
+				return err
+			}
+
+			applied = append(applied, s.Migration)
+		}
+
+		return nil
+	})
+
+	return applied, err
+}
+
+// Down rolls back the last steps applied migrations and returns them, latest
+// first.
+func Down(ctx context.Context, db *sql.DB, steps int) ([]Migration, error) {
+
+	var rolledBack []Migration
+
+	err := withLock(ctx, db, func(conn *sql.Conn) error {
+
+		statuses, err := status(ctx, conn)
+
+		if err != nil {
+			return err
+		}
+
+		for i := len(statuses) - 1; i >= 0 && len(rolledBack) < steps; i-- {
+
+			s := statuses[i]
+
+			if s.AppliedAt == nil {
+				continue
+			}
+
+			err := run(ctx, conn, s.Migration, s.Down,
+				`DELETE FROM schema_migrations WHERE version = $1`, s.Version)
+
+			if err != nil {
+				return err
+			}
+
+			rolledBack = append(rolledBack, s.Migration)
+		}
+
+		return nil
+	})
+
+	return rolledBack, err
+}
+
+// GetStatus lists every known migration and when it was applied, if it was.
+func GetStatus(ctx context.Context, db *sql.DB) ([]Status, error) {
+
+	var statuses []Status
+
+	err := withLock(ctx, db, func(conn *sql.Conn) error {
+		var err error
+		statuses, err = status(ctx, conn)
+		return err
+	})
+
+	return statuses, err
+}
+
+func withLock(ctx context.Context, db *sql.DB, fn func(conn *sql.Conn) error) error {
+
+	conn, err := db.Conn(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey)
+
+	if err != nil {
+		return err
+	}
+
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey)
+
+	_, err = conn.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    INTEGER PRIMARY KEY,
+			name       TEXT        NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)`)
+
+	if err != nil {
+		return err
+	}
+
+	return fn(conn)
+}
+
+func status(ctx context.Context, conn *sql.Conn) ([]Status, error) {
+
+	migrations, err := Load()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT version, applied_at FROM schema_migrations`)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	appliedAt := make(map[int]time.Time)
+	for rows.Next() {
+		var (
+			version int
+			at      time.Time
+		)
+
+		err := rows.Scan(&version, &at)
+
+		if err != nil {
+			return nil, err
+		}
+
+		appliedAt[version] = at
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var statuses []Status
+	for _, migration := range migrations {
+
+		s := Status{Migration: migration}
+
+		if at, ok := appliedAt[migration.Version]; ok {
+			s.AppliedAt = &at
+		}
+
+		statuses = append(statuses, s)
+	}
+
+	return statuses, nil
+}
+
+// run executes script and the bookkeeping statement in one transaction.
+func run(ctx context.Context, conn *sql.Conn, migration Migration, script, record string, args ...interface{}) error {
+
+	tx, err := conn.BeginTx(ctx, nil)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, script)
+
+	if err == nil {
+		_, err = tx.ExecContext(ctx, record, args...)
+	}
+
+	if err != nil {
+		tx.Rollback()
+		return errors.Wrapf(err, "migrations: %04d_%s", migration.Version, migration.Name)
+	}
+
+	return tx.Commit()
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
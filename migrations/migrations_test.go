@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+
+	migrations, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if len(migrations) == 0 {
+		t.Fatal("expected embedded migrations")
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Errorf("migration %s: versions must be consecutive from 1, got %d", migration, migration.Version)
+		}
+		if migration.Up == "" || migration.Down == "" {
+			t.Errorf("migration %s: missing up or down script", migration)
+		}
+	}
+
+	if migrations[0].String() != "0001_init" {
+		t.Errorf("first migration = %s, want 0001_init", migrations[0])
+	}
+}
+
+func TestInitAdoptsExistingSchema(t *testing.T) {
+
+	migrations, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	// Deployments created before migrations existed already hold these
+	// tables, so the first migration must leave them alone.
+	for _, statement := range strings.Split(migrations[0].Up, ";") {
+		statement = strings.TrimSpace(statement)
+		if strings.HasPrefix(statement, "CREATE") && !strings.Contains(statement, "IF NOT EXISTS") {
+			t.Errorf("0001_init: %q is not idempotent", strings.SplitN(statement, "\n", 2)[0])
+		}
+	}
+}
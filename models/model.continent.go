@@ -46,7 +46,7 @@ func (s ContinentModel) Response() ContinentResponse {
 
 }
 
-func GetOneContinent(ctx context.Context, db *sql.DB, id uuid.UUID) (ContinentModel, error) {
+func GetOneContinent(ctx context.Context, db helpers.Querier, id uuid.UUID) (ContinentModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -82,7 +82,7 @@ func GetOneContinent(ctx context.Context, db *sql.DB, id uuid.UUID) (ContinentMo
 
 }
 
-func GetOneContinentByName(ctx context.Context, db *sql.DB, name string) (ContinentModel, error) {
+func GetOneContinentByName(ctx context.Context, db helpers.Querier, name string) (ContinentModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -118,12 +118,27 @@ func GetOneContinentByName(ctx context.Context, db *sql.DB, name string) (Contin
 
 }
 
-func GetAllContinent(ctx context.Context, db *sql.DB, filter helpers.Filter) ([]ContinentModel, error) {
+// continentSortColumns are the sorts of GetAllContinent.
+var continentSortColumns = map[string]string{
+	SortName: "name",
+}
+
+func continentBuilder(filter helpers.Filter) *helpers.QueryBuilder {
+
+	builder := helpers.NewQueryBuilder(filter)
+	builder.Search("name")
+	builder.Range(noRangeColumns)
+	builder.OrderBy(continentSortColumns, SortName, "id")
+
+	return builder
+}
 
-	var searchQuery string
+func GetAllContinent(ctx context.Context, db helpers.Querier, filter helpers.Filter) ([]ContinentModel, error) {
 
-	if filter.Search != "" {
-		searchQuery = fmt.Sprintf(`WHERE LOWER(name) LIKE LOWER('%%%s%%')`, filter.Search)
+	clauses, args, err := continentBuilder(filter).Build()
+
+	if err != nil {
+		return nil, err
 	}
 
 	query := fmt.Sprintf(`
@@ -136,13 +151,10 @@ func GetAllContinent(ctx context.Context, db *sql.DB, filter helpers.Filter) ([]
 			updated_by,
 			updated_at
 		FROM continent
-		%s 
-		ORDER BY
-			name  %s
-		LIMIT $1 OFFSET $2`,
-		searchQuery, filter.Dir)
+		%s`,
+		clauses)
 
-	rows, err := db.QueryContext(ctx, query, filter.Limit, filter.Offset)
+	rows, err := db.QueryContext(ctx, query, args...)
 
 	if err != nil {
 		return nil, err
@@ -154,7 +166,7 @@ func GetAllContinent(ctx context.Context, db *sql.DB, filter helpers.Filter) ([]
 	for rows.Next() {
 		var continent ContinentModel
 
-		rows.Scan(
+		err := rows.Scan(
 			&continent.Id,
 			&continent.Name,
 			&continent.Code,
@@ -164,14 +176,28 @@ func GetAllContinent(ctx context.Context, db *sql.DB, filter helpers.Filter) ([]
 			&continent.UpdatedAt,
 		)
 
+		if err != nil {
+			return nil, err
+		}
+
 		continents = append(continents, continent)
 	}
 
-	return continents, nil
+	return continents, rows.Err()
 
 }
 
-func (s *ContinentModel) Insert(ctx context.Context, db *sql.DB) error {
+func CountContinent(ctx context.Context, db helpers.Querier, filter helpers.Filter) (int, error) {
+	return countRows(ctx, db, "continent", continentBuilder(filter))
+}
+
+// Cursor returns the cursor of the page of a list sorted as filter asks that
+// ends with s.
+func (s ContinentModel) Cursor(filter helpers.Filter) string {
+	return helpers.NewCursor(filter, &s.Name, s.Id.String())
+}
+
+func (s *ContinentModel) Insert(ctx context.Context, db helpers.Querier) error {
 
 	query := fmt.Sprintf(`
 		INSERT INTO continent(
@@ -197,3 +223,78 @@ func (s *ContinentModel) Insert(ctx context.Context, db *sql.DB) error {
 	return nil
 
 }
+
+func GetOneContinentByCode(ctx context.Context, db helpers.Querier, code string) (ContinentModel, error) {
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			name,	
+			code,
+			created_by,
+			created_at,
+			updated_by,
+			updated_at
+		FROM 
+			continent
+		WHERE 
+			code = UPPER($1)
+	`)
+
+	var continent ContinentModel
+	err := db.QueryRowContext(ctx, query, code).Scan(
+		&continent.Id,
+		&continent.Name,
+		&continent.Code,
+		&continent.CreatedBy,
+		&continent.CreatedAt,
+		&continent.UpdatedBy,
+		&continent.UpdatedAt,
+	)
+
+	if err != nil {
+		return ContinentModel{}, err
+	}
+
+	return continent, nil
+
+}
+
+// Upsert inserts the continent unless one with the same code exists, in which
+// case s is loaded from it. The name of an existing continent is kept.
+func (s *ContinentModel) Upsert(ctx context.Context, db helpers.Querier) (UpsertResult, error) {
+
+	query := fmt.Sprintf(`
+		INSERT INTO continent(
+			name,
+			code,
+			created_by,
+			created_at
+		)VALUES(
+			$1,$2,$3,now())
+		ON CONFLICT (code)
+		DO NOTHING
+		RETURNING
+			id, created_at
+	`)
+
+	err := db.QueryRowContext(ctx, query,
+		s.Name, s.Code, s.CreatedBy).Scan(
+		&s.Id, &s.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		*s, err = GetOneContinentByCode(ctx, db, s.Code)
+		if err != nil {
+			return UpsertUnchanged, err
+		}
+		return UpsertUnchanged, nil
+	}
+
+	if err != nil {
+		return UpsertUnchanged, err
+	}
+
+	return UpsertInserted, nil
+
+}
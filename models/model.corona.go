@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/lib/pq"
 	uuid "github.com/satori/go.uuid"
+	"math"
 	"time"
 )
 
@@ -14,41 +15,92 @@ type (
 	CoronaModel struct {
 		Id             uuid.UUID
 		CountryId      uuid.UUID
-		TotalCases     string
-		NewCases       string
-		TotalDeaths    string
-		NewDeaths      string
-		TotalRecovered string
-		ActiveCases    string
-		SeriousCases   string
-		TotalTests     string
-		Population     string
+		TotalCases     sql.NullInt64
+		NewCases       sql.NullInt64
+		TotalDeaths    sql.NullInt64
+		NewDeaths      sql.NullInt64
+		TotalRecovered sql.NullInt64
+		ActiveCases    sql.NullInt64
+		SeriousCases   sql.NullInt64
+		TotalTests     sql.NullInt64
+		Population     sql.NullInt64
 		CreatedBy      uuid.UUID
 		CreatedAt      time.Time
 		UpdatedBy      uuid.NullUUID
 		UpdatedAt      pq.NullTime
 	}
 
+	// CoronaDerived holds figures computed from the raw ones. Per million values
+	// are relative to the population, rates are percentages. A value is null when
+	// one of its inputs is unknown or zero.
+	CoronaDerived struct {
+		CasesPerMillion  *float64 `json:"cases_per_million"`
+		DeathsPerMillion *float64 `json:"deaths_per_million"`
+		TestsPerMillion  *float64 `json:"tests_per_million"`
+		CaseFatalityRate *float64 `json:"case_fatality_rate"`
+		RecoveryRate     *float64 `json:"recovery_rate"`
+		TestPositivity   *float64 `json:"test_positivity"`
+	}
+
 	CoronaResponse struct {
 		Id             uuid.UUID       `json:"id"`
 		Country        CountryResponse `json:"country"`
-		TotalCases     string          `json:"total_cases"`
-		NewCases       string          `json:"new_cases"`
-		TotalDeaths    string          `json:"total_deaths"`
-		NewDeaths      string          `json:"new_deaths"`
-		TotalRecovered string          `json:"total_recovered"`
-		ActiveCases    string          `json:"active_cases"`
-		SeriousCases   string          `json:"serious_cases"`
-		TotalTests     string          `json:"total_tests"`
-		Population     string          `json:"population"`
+		TotalCases     *int64          `json:"total_cases"`
+		NewCases       *int64          `json:"new_cases"`
+		TotalDeaths    *int64          `json:"total_deaths"`
+		NewDeaths      *int64          `json:"new_deaths"`
+		TotalRecovered *int64          `json:"total_recovered"`
+		ActiveCases    *int64          `json:"active_cases"`
+		SeriousCases   *int64          `json:"serious_cases"`
+		TotalTests     *int64          `json:"total_tests"`
+		Population     *int64          `json:"population"`
+		Derived        CoronaDerived   `json:"derived"`
 		CreatedBy      uuid.UUID       `json:"created_by"`
 		CreatedAt      time.Time       `json:"created_at"`
 		UpdatedBy      uuid.UUID       `json:"updated_by"`
 		UpdatedAt      time.Time       `json:"updated_at"`
 	}
+
+	// CoronaDetailModel is the figures of a country read along with the country
+	// and its continent, so lists can build their responses without a query per row.
+	CoronaDetailModel struct {
+		Corona  CoronaModel
+		Country CountryDetailModel
+	}
 )
 
-func (s CoronaModel) Response(ctx context.Context, db *sql.DB, logger *helpers.Logger) (CoronaResponse, error) {
+// coronaDetailColumns selects a CoronaDetailModel from corona_data cd joined with
+// country c and continent co, in the order of CoronaDetailModel.fields.
+const coronaDetailColumns = `
+			cd.id,
+			cd.country_id,
+			cd.total_cases,
+			cd.new_cases,
+			cd.total_deaths,
+			cd.new_deaths,
+			cd.total_recovered,
+			cd.active_cases,
+			cd.serious_cases,
+			cd.total_tests,
+			cd.population,
+			cd.created_by,
+			cd.created_at,
+			cd.updated_by,
+			cd.updated_at,` + countryDetailColumns
+
+// coronaDetailFrom joins corona_data cd with country c and continent co.
+const coronaDetailFrom = `
+			corona_data cd
+		INNER JOIN
+			country c
+		ON
+			cd.country_id = c.id
+		INNER JOIN
+			continent co
+		ON
+			c.continent_id = co.id`
+
+func (s CoronaModel) Response(ctx context.Context, db helpers.Querier, logger *helpers.Logger) (CoronaResponse, error) {
 
 	country, err := GetOneCountry(ctx, db, s.CountryId)
 
@@ -57,33 +109,102 @@ func (s CoronaModel) Response(ctx context.Context, db *sql.DB, logger *helpers.L
 		return CoronaResponse{}, err
 	}
 
-	countryResponse, err := country.Response(ctx, db, logger)
+	continent, err := GetOneContinent(ctx, db, country.ContinentId)
 
 	if err != nil {
-		logger.Err.Printf(`model.corona.go/country.Response/%v`, err)
+		logger.Err.Printf(`model.corona.go/GetOneContinent/%v`, err)
 		return CoronaResponse{}, err
 	}
 
+	return CoronaDetailModel{
+		Corona:  s,
+		Country: CountryDetailModel{Country: country, Continent: continent},
+	}.Response(), nil
+}
+
+// ISOCode returns the alpha-2 code of the country, which map formats locate the
+// figures by.
+func (s CoronaResponse) ISOCode() string {
+	return s.Country.Code
+}
+
+func (s CoronaDetailModel) Response() CoronaResponse {
+
 	return CoronaResponse{
-		Id:             s.Id,
-		Country:        countryResponse,
-		TotalCases:     s.TotalCases,
-		NewCases:       s.NewCases,
-		TotalDeaths:    s.TotalDeaths,
-		NewDeaths:      s.NewDeaths,
-		TotalRecovered: s.TotalRecovered,
-		ActiveCases:    s.ActiveCases,
-		SeriousCases:   s.SeriousCases,
-		TotalTests:     s.TotalTests,
-		Population:     s.Population,
-		CreatedBy:      s.CreatedBy,
-		CreatedAt:      s.CreatedAt,
-		UpdatedBy:      s.UpdatedBy.UUID,
-		UpdatedAt:      s.UpdatedAt.Time,
-	}, nil
+		Id:             s.Corona.Id,
+		Country:        s.Country.Response(),
+		TotalCases:     nullInt64(s.Corona.TotalCases),
+		NewCases:       nullInt64(s.Corona.NewCases),
+		TotalDeaths:    nullInt64(s.Corona.TotalDeaths),
+		NewDeaths:      nullInt64(s.Corona.NewDeaths),
+		TotalRecovered: nullInt64(s.Corona.TotalRecovered),
+		ActiveCases:    nullInt64(s.Corona.ActiveCases),
+		SeriousCases:   nullInt64(s.Corona.SeriousCases),
+		TotalTests:     nullInt64(s.Corona.TotalTests),
+		Population:     nullInt64(s.Corona.Population),
+		Derived:        s.Corona.Derived(),
+		CreatedBy:      s.Corona.CreatedBy,
+		CreatedAt:      s.Corona.CreatedAt,
+		UpdatedBy:      s.Corona.UpdatedBy.UUID,
+		UpdatedAt:      s.Corona.UpdatedAt.Time,
+	}
+}
+
+func (s *CoronaDetailModel) fields() []interface{} {
+
+	return append([]interface{}{
+		&s.Corona.Id,
+		&s.Corona.CountryId,
+		&s.Corona.TotalCases,
+		&s.Corona.NewCases,
+		&s.Corona.TotalDeaths,
+		&s.Corona.NewDeaths,
+		&s.Corona.TotalRecovered,
+		&s.Corona.ActiveCases,
+		&s.Corona.SeriousCases,
+		&s.Corona.TotalTests,
+		&s.Corona.Population,
+		&s.Corona.CreatedBy,
+		&s.Corona.CreatedAt,
+		&s.Corona.UpdatedBy,
+		&s.Corona.UpdatedAt,
+	}, s.Country.fields()...)
+}
+
+func (s CoronaModel) Derived() CoronaDerived {
+
+	return CoronaDerived{
+		CasesPerMillion:  ratio(s.TotalCases, s.Population, 1e6),
+		DeathsPerMillion: ratio(s.TotalDeaths, s.Population, 1e6),
+		TestsPerMillion:  ratio(s.TotalTests, s.Population, 1e6),
+		CaseFatalityRate: ratio(s.TotalDeaths, s.TotalCases, 100),
+		RecoveryRate:     ratio(s.TotalRecovered, s.TotalCases, 100),
+		TestPositivity:   ratio(s.TotalCases, s.TotalTests, 100),
+	}
+
+}
+
+// ratio returns numerator / denominator * scale rounded to two decimals.
+func ratio(numerator, denominator sql.NullInt64, scale float64) *float64 {
+
+	if !numerator.Valid || !denominator.Valid || denominator.Int64 == 0 {
+		return nil
+	}
+
+	value := math.Round(float64(numerator.Int64)/float64(denominator.Int64)*scale*100) / 100
+
+	return &value
+}
+
+// nullInt64 exposes an unknown figure as a JSON null instead of zero.
+func nullInt64(value sql.NullInt64) *int64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int64
 }
 
-func GetOneCorona(ctx context.Context, db *sql.DB, id uuid.UUID) (CoronaModel, error) {
+func GetOneCorona(ctx context.Context, db helpers.Querier, id uuid.UUID) (CoronaModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -135,7 +256,7 @@ func GetOneCorona(ctx context.Context, db *sql.DB, id uuid.UUID) (CoronaModel, e
 
 }
 
-func GetCoronaByCountry(ctx context.Context, db *sql.DB, country string) (CoronaModel, error) {
+func GetCoronaByCountry(ctx context.Context, db helpers.Querier, country string) (CoronaModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -191,112 +312,113 @@ func GetCoronaByCountry(ctx context.Context, db *sql.DB, country string) (Corona
 
 }
 
-func GetAllCoronaByContinent(ctx context.Context, db *sql.DB, filter helpers.Filter, id uuid.UUID) (
-	[]CoronaModel, error) {
+func coronaByContinentBuilder(filter helpers.Filter, id uuid.UUID) *helpers.QueryBuilder {
 
-	query := fmt.Sprintf(`
-		SELECT
-			cd.id,
-			country_id,
-			total_cases,
-			new_cases,
-			total_deaths,
-			new_deaths,
-			total_recovered,
-			active_cases,
-			serious_cases,
-			total_tests,
-			population,
-			cd.created_by,
-			cd.created_at,
-			cd.updated_by,
-			cd.updated_at
-		FROM 
-			corona_data cd
-		INNER JOIN 
-			country c
-		ON
-			cd.country_id = c.id
-		WHERE 
-			c.continent_id = $1
-		ORDER BY 
-			c.name %s
-		LIMIT $2 OFFSET $3`, filter.Dir)
+	builder := helpers.NewQueryBuilder(filter)
+	builder.Where("c.continent_id = ?", id)
+	filterCorona(builder, filter)
+	builder.OrderBy(coronaSortColumns, SortName, "c.id")
+
+	return builder
+}
 
-	rows, err := db.QueryContext(ctx, query, id, filter.Limit, filter.Offset)
+func GetAllCoronaByContinent(ctx context.Context, db helpers.Querier, filter helpers.Filter, id uuid.UUID) (
+	[]CoronaDetailModel, error) {
+
+	clauses, args, err := coronaByContinentBuilder(filter, id).Build()
 
 	if err != nil {
 		return nil, err
 	}
 
-	defer rows.Close()
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		%s`, coronaDetailColumns, coronaDetailFrom, clauses)
 
-	var datas []CoronaModel
-	for rows.Next() {
-		var data CoronaModel
-
-		rows.Scan(
-			&data.Id,
-			&data.CountryId,
-			&data.TotalCases,
-			&data.NewCases,
-			&data.TotalDeaths,
-			&data.NewDeaths,
-			&data.TotalRecovered,
-			&data.ActiveCases,
-			&data.SeriousCases,
-			&data.TotalTests,
-			&data.Population,
-			&data.CreatedBy,
-			&data.CreatedAt,
-			&data.UpdatedBy,
-			&data.UpdatedAt,
-		)
+	return queryCoronaDetails(ctx, db, query, args...)
 
-		datas = append(datas, data)
+}
+
+func CountCoronaByContinent(ctx context.Context, db helpers.Querier, filter helpers.Filter, id uuid.UUID) (int, error) {
+	return countRows(ctx, db, coronaDetailFrom, coronaByContinentBuilder(filter, id))
+}
+
+func coronaBuilder(filter helpers.Filter) *helpers.QueryBuilder {
+
+	builder := helpers.NewQueryBuilder(filter)
+	builder.Search("c.name")
+	filterCorona(builder, filter)
+	builder.OrderBy(coronaSortColumns, SortName, "c.id")
+
+	return builder
+}
+
+func GetAllCorona(ctx context.Context, db helpers.Querier, filter helpers.Filter) ([]CoronaDetailModel, error) {
+
+	clauses, args, err := coronaBuilder(filter).Build()
+
+	if err != nil {
+		return nil, err
 	}
 
-	return datas, nil
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		%s`, coronaDetailColumns, coronaDetailFrom, clauses)
+
+	return queryCoronaDetails(ctx, db, query, args...)
 
 }
 
-func GetAllCorona(ctx context.Context, db *sql.DB, filter helpers.Filter) ([]CoronaModel, error) {
+// GetAllCoronaByCountries returns the figures of the countries of ids, by
+// country name. Countries without figures are left out.
+func GetAllCoronaByCountries(ctx context.Context, db helpers.Querier, ids []uuid.UUID) ([]CoronaDetailModel, error) {
 
-	var searchQuery string
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, id.String())
+	}
+
+	builder := helpers.NewQueryBuilder(helpers.Filter{FilterOption: helpers.FilterOption{Limit: len(ids)}})
+	builder.Where("c.id = ANY(?::uuid[])", pq.Array(keys))
+	builder.OrderBy(coronaSortColumns, SortName, "c.id")
+
+	clauses, args, err := builder.Build()
 
-	if filter.Search != "" {
-		searchQuery = fmt.Sprintf(`WHERE LOWER(c.name) LIKE LOWER('%%%s%%')`, filter.Search)
+	if err != nil {
+		return nil, err
 	}
 
 	query := fmt.Sprintf(`
-		SELECT
-			cd.id,
-			country_id,
-			total_cases,
-			new_cases,
-			total_deaths,
-			new_deaths,
-			total_recovered,
-			active_cases,
-			serious_cases,
-			total_tests,
-			population,
-			cd.created_by,
-			cd.created_at,
-			cd.updated_by,
-			cd.updated_at
-		FROM 
-			corona_data cd
-		INNER JOIN 
-			country c
-		ON
-			cd.country_id = c.id
-		%s
-		ORDER BY 
-			c.name %s
-		LIMIT $1 OFFSET $2`, searchQuery, filter.Dir)
+		SELECT %s
+		FROM %s
+		%s`, coronaDetailColumns, coronaDetailFrom, clauses)
+
+	return queryCoronaDetails(ctx, db, query, args...)
+
+}
+
+func CountCorona(ctx context.Context, db helpers.Querier, filter helpers.Filter) (int, error) {
+	return countRows(ctx, db, coronaDetailFrom, coronaBuilder(filter))
+}
+
+// Cursor returns the cursor of the page of a list sorted as filter asks that
+// ends with s.
+func (s CoronaDetailModel) Cursor(filter helpers.Filter) string {
+
+	value := &s.Country.Country.Name
+	if filter.Sort != "" && filter.Sort != SortName {
+		value = cursorValue(s.Corona.MetricValue(filter.Sort))
+	}
 
-	rows, err := db.QueryContext(ctx, query, filter.Limit, filter.Offset)
+	return helpers.NewCursor(filter, value, s.Country.Country.Id.String())
+}
+
+func queryCoronaDetails(ctx context.Context, db helpers.Querier, query string, args ...interface{}) (
+	[]CoronaDetailModel, error) {
+
+	rows, err := db.QueryContext(ctx, query, args...)
 
 	if err != nil {
 		return nil, err
@@ -304,36 +426,24 @@ func GetAllCorona(ctx context.Context, db *sql.DB, filter helpers.Filter) ([]Cor
 
 	defer rows.Close()
 
-	var datas []CoronaModel
+	var datas []CoronaDetailModel
 	for rows.Next() {
-		var data CoronaModel
-
-		rows.Scan(
-			&data.Id,
-			&data.CountryId,
-			&data.TotalCases,
-			&data.NewCases,
-			&data.TotalDeaths,
-			&data.NewDeaths,
-			&data.TotalRecovered,
-			&data.ActiveCases,
-			&data.SeriousCases,
-			&data.TotalTests,
-			&data.Population,
-			&data.CreatedBy,
-			&data.CreatedAt,
-			&data.UpdatedBy,
-			&data.UpdatedAt,
-		)
+		var data CoronaDetailModel
+
+		err := rows.Scan(data.fields()...)
+
+		if err != nil {
+			return nil, err
+		}
 
 		datas = append(datas, data)
 	}
 
-	return datas, nil
+	return datas, rows.Err()
 
 }
 
-func (s *CoronaModel) Insert(ctx context.Context, db *sql.DB) error {
+func (s *CoronaModel) Insert(ctx context.Context, db helpers.Querier) error {
 
 	query := fmt.Sprintf(`
 		INSERT INTO corona_data(
@@ -369,7 +479,7 @@ func (s *CoronaModel) Insert(ctx context.Context, db *sql.DB) error {
 
 }
 
-func (s *CoronaModel) Update(ctx context.Context, db *sql.DB) error {
+func (s *CoronaModel) Update(ctx context.Context, db helpers.Querier) error {
 
 	query := fmt.Sprintf(`
 		UPDATE corona_data
@@ -404,3 +514,67 @@ func (s *CoronaModel) Update(ctx context.Context, db *sql.DB) error {
 	return nil
 
 }
+
+// Upsert inserts the figures of the country or replaces the ones it has. A row
+// whose figures did not change is left untouched and reported as unchanged.
+func (s *CoronaModel) Upsert(ctx context.Context, db helpers.Querier) (UpsertResult, error) {
+
+	query := fmt.Sprintf(`
+		INSERT INTO corona_data(
+			country_id,
+			total_cases,
+			new_cases,
+			total_deaths,
+			new_deaths,
+			total_recovered,
+			active_cases,
+			serious_cases,
+			total_tests,
+			population,
+			created_by,
+			created_at
+		)VALUES(
+			$1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,now())
+		ON CONFLICT (country_id)
+		DO UPDATE SET
+			total_cases=EXCLUDED.total_cases,
+			new_cases=EXCLUDED.new_cases,
+			total_deaths=EXCLUDED.total_deaths,
+			new_deaths=EXCLUDED.new_deaths,
+			total_recovered=EXCLUDED.total_recovered,
+			active_cases=EXCLUDED.active_cases,
+			serious_cases=EXCLUDED.serious_cases,
+			total_tests=EXCLUDED.total_tests,
+			population=EXCLUDED.population,
+			updated_at=NOW(),
+			updated_by=EXCLUDED.created_by
+		WHERE
+			(corona_data.total_cases, corona_data.new_cases, corona_data.total_deaths, corona_data.new_deaths,
+			corona_data.total_recovered, corona_data.active_cases, corona_data.serious_cases,
+			corona_data.total_tests, corona_data.population)
+			IS DISTINCT FROM
+			(EXCLUDED.total_cases, EXCLUDED.new_cases, EXCLUDED.total_deaths, EXCLUDED.new_deaths,
+			EXCLUDED.total_recovered, EXCLUDED.active_cases, EXCLUDED.serious_cases,
+			EXCLUDED.total_tests, EXCLUDED.population)
+		RETURNING
+			id, created_at, created_by, updated_at, (xmax = 0)
+	`)
+
+	var inserted bool
+	err := db.QueryRowContext(ctx, query,
+		s.CountryId, s.TotalCases, s.NewCases, s.TotalDeaths, s.NewDeaths, s.TotalRecovered, s.ActiveCases, s.SeriousCases,
+		s.TotalTests, s.Population, s.CreatedBy).Scan(
+		&s.Id, &s.CreatedAt, &s.CreatedBy, &s.UpdatedAt, &inserted,
+	)
+
+	if err == sql.ErrNoRows {
+		return UpsertUnchanged, nil
+	}
+
+	if err != nil {
+		return UpsertUnchanged, err
+	}
+
+	return upsertResult(inserted), nil
+
+}
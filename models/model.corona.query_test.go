@@ -0,0 +1,83 @@
+package models
+
+import (
+	"context"
+	"corona/helpers"
+	"database/sql/driver"
+	uuid "github.com/satori/go.uuid"
+	"testing"
+	"time"
+)
+
+func TestGetAllCoronaQuery(t *testing.T) {
+
+	db, r := newRecorder(t, nil)
+
+	last := "99.5"
+	key := uuid.NewV4().String()
+
+	filter := helpers.Filter{
+		FilterOption: helpers.FilterOption{Limit: 2, Search: "ger", Sort: DerivedCasesPerMillion, Dir: "desc"},
+		Continent:    "Europe",
+		Ranges:       []helpers.Range{{Field: MetricTotalCases, Op: helpers.RangeGte, Value: 1000}},
+		Cursor:       &helpers.Cursor{Sort: DerivedCasesPerMillion, Dir: "DESC", Value: &last, Key: key},
+	}
+
+	if _, err := GetAllCorona(context.Background(), db, filter); err != nil {
+		t.Fatal(err)
+	}
+
+	r.checkQuery(t, "get_all_corona", "%ger%", "Europe", "Europe", 1000.0, last, last, key, int64(2), int64(0))
+}
+
+func TestGetTopCoronaQuery(t *testing.T) {
+
+	db, r := newRecorder(t, nil)
+
+	continentId := uuid.NewV4()
+
+	_, err := GetTopCorona(context.Background(), db, DerivedDeathsPerMillion, 5,
+		uuid.NullUUID{UUID: continentId, Valid: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.checkQuery(t, "get_top_corona", continentId.String(), int64(5), int64(0))
+}
+
+func TestCoronaUpsertQuery(t *testing.T) {
+
+	columns := []string{"id", "created_at", "created_by", "updated_at", "inserted"}
+	now := time.Now()
+
+	for _, test := range []struct {
+		rows [][]driver.Value
+		want UpsertResult
+	}{
+		{[][]driver.Value{{uuid.NewV4().String(), now, uuid.NewV4().String(), nil, true}}, UpsertInserted},
+		{[][]driver.Value{{uuid.NewV4().String(), now, uuid.NewV4().String(), now, false}}, UpsertUpdated},
+		{nil, UpsertUnchanged},
+	} {
+		db, r := newRecorder(t, columns, test.rows...)
+
+		data := CoronaModel{
+			CountryId:  uuid.NewV4(),
+			TotalCases: count(10),
+			Population: count(1000),
+			CreatedBy:  uuid.NewV4(),
+		}
+		createdBy := data.CreatedBy
+
+		result, err := data.Upsert(context.Background(), db)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result != test.want {
+			t.Errorf("got %s, want %s", result, test.want)
+		}
+
+		r.checkQuery(t, "corona_upsert", data.CountryId.String(), int64(10), nil, nil, nil, nil, nil, nil, nil,
+			int64(1000), createdBy.String())
+	}
+}
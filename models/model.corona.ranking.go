@@ -0,0 +1,179 @@
+package models
+
+import (
+	"context"
+	"corona/helpers"
+	"fmt"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+const (
+	SortName = "name"
+
+	DerivedCasesPerMillion  = "cases_per_million"
+	DerivedDeathsPerMillion = "deaths_per_million"
+	DerivedTestsPerMillion  = "tests_per_million"
+	DerivedCaseFatalityRate = "case_fatality_rate"
+	DerivedRecoveryRate     = "recovery_rate"
+	DerivedTestPositivity   = "test_positivity"
+)
+
+// DerivedMetrics are the names of the values of CoronaDerived.
+var DerivedMetrics = []string{
+	DerivedCasesPerMillion,
+	DerivedDeathsPerMillion,
+	DerivedTestsPerMillion,
+	DerivedCaseFatalityRate,
+	DerivedRecoveryRate,
+	DerivedTestPositivity,
+}
+
+// coronaMetricColumns maps the metrics corona lists can be sorted and filtered
+// by to their expression on corona_data cd. Anything else is rejected rather
+// than written into a query.
+var coronaMetricColumns = map[string]string{
+	MetricTotalCases:        "cd.total_cases",
+	MetricNewCases:          "cd.new_cases",
+	MetricTotalDeaths:       "cd.total_deaths",
+	MetricNewDeaths:         "cd.new_deaths",
+	MetricTotalRecovered:    "cd.total_recovered",
+	MetricActiveCases:       "cd.active_cases",
+	MetricSeriousCases:      "cd.serious_cases",
+	MetricTotalTests:        "cd.total_tests",
+	MetricPopulation:        "cd.population",
+	DerivedCasesPerMillion:  derivedColumn("cd.total_cases", "cd.population", "1e6"),
+	DerivedDeathsPerMillion: derivedColumn("cd.total_deaths", "cd.population", "1e6"),
+	DerivedTestsPerMillion:  derivedColumn("cd.total_tests", "cd.population", "1e6"),
+	DerivedCaseFatalityRate: derivedColumn("cd.total_deaths", "cd.total_cases", "100"),
+	DerivedRecoveryRate:     derivedColumn("cd.total_recovered", "cd.total_cases", "100"),
+	DerivedTestPositivity:   derivedColumn("cd.total_cases", "cd.total_tests", "100"),
+}
+
+// derivedColumn computes a derived value the way ratio does, rounded to two
+// decimals, so lists are sorted, bounded and paged by the values they show.
+func derivedColumn(numerator, denominator, scale string) string {
+	return fmt.Sprintf("ROUND((%s::float8 / NULLIF(%s, 0) * %s)::numeric, 2)", numerator, denominator, scale)
+}
+
+// coronaSortColumns adds the country name of c to coronaMetricColumns.
+var coronaSortColumns = func() map[string]string {
+
+	columns := map[string]string{SortName: "c.name"}
+	for name, column := range coronaMetricColumns {
+		columns[name] = column
+	}
+
+	return columns
+}()
+
+// IsCoronaSort reports whether corona lists can be sorted by name: the country
+// name, one of CoronaMetrics or one of DerivedMetrics.
+func IsCoronaSort(name string) bool {
+	_, ok := coronaSortColumns[name]
+	return ok
+}
+
+// IsRankingMetric reports whether countries can be ranked and corona lists
+// bounded by name, which is any sort but the country name.
+func IsRankingMetric(name string) bool {
+	_, ok := coronaMetricColumns[name]
+	return ok
+}
+
+// MetricValue returns the figure or derived value name refers to, or nil when it
+// is unknown.
+func (s CoronaModel) MetricValue(name string) *float64 {
+
+	derived := s.Derived()
+
+	switch name {
+	case DerivedCasesPerMillion:
+		return derived.CasesPerMillion
+	case DerivedDeathsPerMillion:
+		return derived.DeathsPerMillion
+	case DerivedTestsPerMillion:
+		return derived.TestsPerMillion
+	case DerivedCaseFatalityRate:
+		return derived.CaseFatalityRate
+	case DerivedRecoveryRate:
+		return derived.RecoveryRate
+	case DerivedTestPositivity:
+		return derived.TestPositivity
+	}
+
+	figure := NewCoronaSnapshot(s, time.Time{}).Metric(name)
+
+	if !figure.Valid {
+		return nil
+	}
+
+	value := float64(figure.Int64)
+
+	return &value
+}
+
+// MetricValue returns the figure or derived value name refers to as it was on
+// the day of the snapshot, or nil when it is unknown.
+func (s CoronaSnapshotModel) MetricValue(name string) *float64 {
+	return CoronaModel{
+		TotalCases:     s.TotalCases,
+		NewCases:       s.NewCases,
+		TotalDeaths:    s.TotalDeaths,
+		NewDeaths:      s.NewDeaths,
+		TotalRecovered: s.TotalRecovered,
+		ActiveCases:    s.ActiveCases,
+		SeriousCases:   s.SeriousCases,
+		TotalTests:     s.TotalTests,
+		Population:     s.Population,
+	}.MetricValue(name)
+}
+
+// filterCorona adds the attribute and range filters of corona lists to builder:
+// the continent, by name or code, the bounds on metrics and the last update.
+func filterCorona(builder *helpers.QueryBuilder, filter helpers.Filter) {
+
+	if filter.Continent != "" {
+		builder.Where("(LOWER(co.name) = LOWER(?) OR LOWER(co.code) = LOWER(?))", filter.Continent, filter.Continent)
+	}
+
+	if !filter.UpdatedSince.IsZero() {
+		builder.Where("COALESCE(cd.updated_at, cd.created_at) >= ?", filter.UpdatedSince)
+	}
+
+	builder.Range(coronaMetricColumns)
+}
+
+// GetTopCorona returns the limit countries with the highest known value of
+// metric, optionally only those of a continent.
+func GetTopCorona(ctx context.Context, db helpers.Querier, metric string, limit int, continentId uuid.NullUUID) (
+	[]CoronaDetailModel, error) {
+
+	if !IsRankingMetric(metric) {
+		return nil, errors.Wrapf(helpers.ErrInvalidFilter, "unknown ranking metric %q", metric)
+	}
+
+	builder := helpers.NewQueryBuilder(helpers.Filter{
+		FilterOption: helpers.FilterOption{Limit: limit, Sort: metric, Dir: "desc"},
+	})
+	builder.Where(coronaMetricColumns[metric] + " IS NOT NULL")
+	if continentId.Valid {
+		builder.Where("c.continent_id = ?", continentId.UUID)
+	}
+	builder.OrderBy(coronaSortColumns, metric, "c.id")
+
+	clauses, args, err := builder.Build()
+
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s
+		%s`, coronaDetailColumns, coronaDetailFrom, clauses)
+
+	return queryCoronaDetails(ctx, db, query, args...)
+
+}
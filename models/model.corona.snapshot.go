@@ -0,0 +1,228 @@
+package models
+
+import (
+	"context"
+	"corona/helpers"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+type (
+	// CoronaSnapshotModel is the figures of one country as they were on ReportDate.
+	CoronaSnapshotModel struct {
+		Id             uuid.UUID
+		CountryId      uuid.UUID
+		ReportDate     time.Time
+		TotalCases     sql.NullInt64
+		NewCases       sql.NullInt64
+		TotalDeaths    sql.NullInt64
+		NewDeaths      sql.NullInt64
+		TotalRecovered sql.NullInt64
+		ActiveCases    sql.NullInt64
+		SeriousCases   sql.NullInt64
+		TotalTests     sql.NullInt64
+		Population     sql.NullInt64
+		CreatedBy      uuid.UUID
+		CreatedAt      time.Time
+		UpdatedBy      uuid.NullUUID
+		UpdatedAt      pq.NullTime
+	}
+)
+
+// CoronaMetrics are the names of the figures kept per country, in the order they
+// are published.
+var CoronaMetrics = []string{
+	MetricTotalCases,
+	MetricNewCases,
+	MetricTotalDeaths,
+	MetricNewDeaths,
+	MetricTotalRecovered,
+	MetricActiveCases,
+	MetricSeriousCases,
+	MetricTotalTests,
+	MetricPopulation,
+}
+
+const (
+	MetricTotalCases     = "total_cases"
+	MetricNewCases       = "new_cases"
+	MetricTotalDeaths    = "total_deaths"
+	MetricNewDeaths      = "new_deaths"
+	MetricTotalRecovered = "total_recovered"
+	MetricActiveCases    = "active_cases"
+	MetricSeriousCases   = "serious_cases"
+	MetricTotalTests     = "total_tests"
+	MetricPopulation     = "population"
+)
+
+func IsCoronaMetric(name string) bool {
+	for _, metric := range CoronaMetrics {
+		if metric == name {
+			return true
+		}
+	}
+	return false
+}
+
+func NewCoronaSnapshot(data CoronaModel, reportDate time.Time) CoronaSnapshotModel {
+	return CoronaSnapshotModel{
+		CountryId:      data.CountryId,
+		ReportDate:     reportDate,
+		TotalCases:     data.TotalCases,
+		NewCases:       data.NewCases,
+		TotalDeaths:    data.TotalDeaths,
+		NewDeaths:      data.NewDeaths,
+		TotalRecovered: data.TotalRecovered,
+		ActiveCases:    data.ActiveCases,
+		SeriousCases:   data.SeriousCases,
+		TotalTests:     data.TotalTests,
+		Population:     data.Population,
+	}
+}
+
+// Metric returns the figure named by one of CoronaMetrics.
+func (s CoronaSnapshotModel) Metric(name string) sql.NullInt64 {
+	switch name {
+	case MetricTotalCases:
+		return s.TotalCases
+	case MetricNewCases:
+		return s.NewCases
+	case MetricTotalDeaths:
+		return s.TotalDeaths
+	case MetricNewDeaths:
+		return s.NewDeaths
+	case MetricTotalRecovered:
+		return s.TotalRecovered
+	case MetricActiveCases:
+		return s.ActiveCases
+	case MetricSeriousCases:
+		return s.SeriousCases
+	case MetricTotalTests:
+		return s.TotalTests
+	case MetricPopulation:
+		return s.Population
+	}
+	return sql.NullInt64{}
+}
+
+func GetAllCoronaSnapshotByCountry(ctx context.Context, db helpers.Querier, countryId uuid.UUID, from, to time.Time) (
+	[]CoronaSnapshotModel, error) {
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			country_id,
+			report_date,
+			total_cases,
+			new_cases,
+			total_deaths,
+			new_deaths,
+			total_recovered,
+			active_cases,
+			serious_cases,
+			total_tests,
+			population,
+			created_by,
+			created_at,
+			updated_by,
+			updated_at
+		FROM
+			corona_snapshot
+		WHERE
+			country_id = $1 AND report_date BETWEEN $2 AND $3
+		ORDER BY
+			report_date ASC
+	`)
+
+	rows, err := db.QueryContext(ctx, query, countryId, from, to)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var snapshots []CoronaSnapshotModel
+	for rows.Next() {
+		var snapshot CoronaSnapshotModel
+
+		rows.Scan(
+			&snapshot.Id,
+			&snapshot.CountryId,
+			&snapshot.ReportDate,
+			&snapshot.TotalCases,
+			&snapshot.NewCases,
+			&snapshot.TotalDeaths,
+			&snapshot.NewDeaths,
+			&snapshot.TotalRecovered,
+			&snapshot.ActiveCases,
+			&snapshot.SeriousCases,
+			&snapshot.TotalTests,
+			&snapshot.Population,
+			&snapshot.CreatedBy,
+			&snapshot.CreatedAt,
+			&snapshot.UpdatedBy,
+			&snapshot.UpdatedAt,
+		)
+
+		snapshots = append(snapshots, snapshot)
+	}
+
+	return snapshots, nil
+
+}
+
+// Upsert appends the snapshot of the day. Past days are never touched, a second
+// run on the same report date refreshes that day's figures.
+func (s *CoronaSnapshotModel) Upsert(ctx context.Context, db helpers.Querier) error {
+
+	query := fmt.Sprintf(`
+		INSERT INTO corona_snapshot(
+			country_id,
+			report_date,
+			total_cases,
+			new_cases,
+			total_deaths,
+			new_deaths,
+			total_recovered,
+			active_cases,
+			serious_cases,
+			total_tests,
+			population,
+			created_by,
+			created_at
+		)VALUES(
+			$1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,now())
+		ON CONFLICT (country_id, report_date)
+		DO UPDATE SET
+			total_cases=EXCLUDED.total_cases,
+			new_cases=EXCLUDED.new_cases,
+			total_deaths=EXCLUDED.total_deaths,
+			new_deaths=EXCLUDED.new_deaths,
+			total_recovered=EXCLUDED.total_recovered,
+			active_cases=EXCLUDED.active_cases,
+			serious_cases=EXCLUDED.serious_cases,
+			total_tests=EXCLUDED.total_tests,
+			population=EXCLUDED.population,
+			updated_at=NOW(),
+			updated_by=EXCLUDED.created_by
+		RETURNING
+			id, created_at, created_by
+	`)
+
+	err := db.QueryRowContext(ctx, query,
+		s.CountryId, s.ReportDate, s.TotalCases, s.NewCases, s.TotalDeaths, s.NewDeaths, s.TotalRecovered,
+		s.ActiveCases, s.SeriousCases, s.TotalTests, s.Population, s.CreatedBy).Scan(
+		&s.Id, &s.CreatedAt, &s.CreatedBy,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+
+}
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"context"
+	"corona/helpers"
+	"database/sql"
+	"fmt"
+	uuid "github.com/satori/go.uuid"
+)
+
+type (
+	// CoronaTotalModel is the figures of a continent summed over its countries.
+	// Countries counts the countries that have figures.
+	CoronaTotalModel struct {
+		Continent      ContinentModel
+		Countries      int
+		TotalCases     sql.NullInt64
+		TotalDeaths    sql.NullInt64
+		TotalRecovered sql.NullInt64
+		ActiveCases    sql.NullInt64
+		TotalTests     sql.NullInt64
+		Population     sql.NullInt64
+	}
+
+	CoronaTotalResponse struct {
+		Continent          ContinentResponse `json:"continent"`
+		ReportingCountries int               `json:"reporting_countries"`
+		TotalCases         *int64            `json:"total_cases"`
+		TotalDeaths        *int64            `json:"total_deaths"`
+		TotalRecovered     *int64            `json:"total_recovered"`
+		ActiveCases        *int64            `json:"active_cases"`
+		TotalTests         *int64            `json:"total_tests"`
+		Population         *int64            `json:"population"`
+	}
+)
+
+func (s CoronaTotalModel) Response() CoronaTotalResponse {
+
+	return CoronaTotalResponse{
+		Continent:          s.Continent.Response(),
+		ReportingCountries: s.Countries,
+		TotalCases:         nullInt64(s.TotalCases),
+		TotalDeaths:        nullInt64(s.TotalDeaths),
+		TotalRecovered:     nullInt64(s.TotalRecovered),
+		ActiveCases:        nullInt64(s.ActiveCases),
+		TotalTests:         nullInt64(s.TotalTests),
+		Population:         nullInt64(s.Population),
+	}
+
+}
+
+const coronaTotalQuery = `
+		SELECT
+			co.id,
+			co.name,
+			co.code,
+			co.created_by,
+			co.created_at,
+			co.updated_by,
+			co.updated_at,
+			COUNT(cd.id),
+			SUM(cd.total_cases)::BIGINT,
+			SUM(cd.total_deaths)::BIGINT,
+			SUM(cd.total_recovered)::BIGINT,
+			SUM(cd.active_cases)::BIGINT,
+			SUM(cd.total_tests)::BIGINT,
+			SUM(cd.population)::BIGINT
+		FROM
+			continent co
+		LEFT JOIN
+			country c
+		ON
+			c.continent_id = co.id
+		LEFT JOIN
+			corona_data cd
+		ON
+			cd.country_id = c.id
+		%s
+		GROUP BY
+			co.id
+		ORDER BY
+			co.name`
+
+func GetCoronaTotalByContinent(ctx context.Context, db helpers.Querier, continentId uuid.UUID) (CoronaTotalModel, error) {
+
+	query := fmt.Sprintf(coronaTotalQuery, `WHERE co.id = $1`)
+
+	rows, err := queryCoronaTotals(ctx, db, query, continentId)
+
+	if err != nil {
+		return CoronaTotalModel{}, err
+	}
+
+	if len(rows) == 0 {
+		return CoronaTotalModel{}, sql.ErrNoRows
+	}
+
+	return rows[0], nil
+
+}
+
+func GetAllCoronaTotal(ctx context.Context, db helpers.Querier) ([]CoronaTotalModel, error) {
+
+	query := fmt.Sprintf(coronaTotalQuery, ``)
+
+	return queryCoronaTotals(ctx, db, query)
+
+}
+
+func queryCoronaTotals(ctx context.Context, db helpers.Querier, query string, args ...interface{}) (
+	[]CoronaTotalModel, error) {
+
+	rows, err := db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var totals []CoronaTotalModel
+	for rows.Next() {
+		var total CoronaTotalModel
+
+		err := rows.Scan(
+			&total.Continent.Id,
+			&total.Continent.Name,
+			&total.Continent.Code,
+			&total.Continent.CreatedBy,
+			&total.Continent.CreatedAt,
+			&total.Continent.UpdatedBy,
+			&total.Continent.UpdatedAt,
+			&total.Countries,
+			&total.TotalCases,
+			&total.TotalDeaths,
+			&total.TotalRecovered,
+			&total.ActiveCases,
+			&total.TotalTests,
+			&total.Population,
+		)
+
+		if err != nil {
+			return nil, err
+		}
+
+		totals = append(totals, total)
+	}
+
+	return totals, rows.Err()
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	uuid "github.com/satori/go.uuid"
+	"testing"
+	"time"
+)
+
+func TestGetCoronaTotalByContinentQuery(t *testing.T) {
+
+	continentId := uuid.NewV4()
+
+	columns := []string{"id", "name", "code", "created_by", "created_at", "updated_by", "updated_at", "count",
+		"total_cases", "total_deaths", "total_recovered", "active_cases", "total_tests", "population"}
+
+	db, r := newRecorder(t, columns, []driver.Value{
+		continentId.String(), "Europe", "EU", uuid.NewV4().String(), time.Now(), nil, nil,
+		int64(3), int64(9800), nil, int64(500), int64(200), nil, int64(80360800),
+	})
+
+	total, err := GetCoronaTotalByContinent(context.Background(), db, continentId)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.checkQuery(t, "get_corona_total_by_continent", continentId.String())
+
+	if total.Continent.Id != continentId || total.Countries != 3 || total.TotalCases != count(9800) ||
+		total.TotalDeaths.Valid || total.Population != count(80360800) {
+		t.Errorf("got total %+v", total)
+	}
+}
+
+func TestGetCoronaTotalByContinentMissing(t *testing.T) {
+
+	db, _ := newRecorder(t, nil)
+
+	if _, err := GetCoronaTotalByContinent(context.Background(), db, uuid.NewV4()); err != sql.ErrNoRows {
+		t.Errorf("got %v for a continent without a row, want %v", err, sql.ErrNoRows)
+	}
+}
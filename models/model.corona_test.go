@@ -0,0 +1,56 @@
+package models
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func count(value int64) sql.NullInt64 {
+	return sql.NullInt64{Int64: value, Valid: true}
+}
+
+func TestCoronaDerived(t *testing.T) {
+
+	data := CoronaModel{
+		TotalCases:     count(2000),
+		TotalDeaths:    count(50),
+		TotalRecovered: count(1500),
+		TotalTests:     count(40000),
+		Population:     count(4000000),
+	}
+
+	derived := data.Derived()
+
+	tests := []struct {
+		name  string
+		value *float64
+		want  float64
+	}{
+		{"cases_per_million", derived.CasesPerMillion, 500},
+		{"deaths_per_million", derived.DeathsPerMillion, 12.5},
+		{"tests_per_million", derived.TestsPerMillion, 10000},
+		{"case_fatality_rate", derived.CaseFatalityRate, 2.5},
+		{"recovery_rate", derived.RecoveryRate, 75},
+		{"test_positivity", derived.TestPositivity, 5},
+	}
+
+	for _, test := range tests {
+		if test.value == nil || *test.value != test.want {
+			t.Errorf("%s = %v, want %v", test.name, test.value, test.want)
+		}
+	}
+}
+
+func TestCoronaDerivedUnknown(t *testing.T) {
+
+	data := CoronaModel{
+		TotalCases:  count(0),
+		TotalDeaths: count(0),
+	}
+
+	derived := data.Derived()
+
+	if derived.CasesPerMillion != nil || derived.CaseFatalityRate != nil || derived.TestPositivity != nil {
+		t.Errorf("expected unknown derived values, got %+v", derived)
+	}
+}
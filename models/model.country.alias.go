@@ -0,0 +1,250 @@
+package models
+
+import (
+	"context"
+	"corona/helpers"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+type (
+	CountryAliasModel struct {
+		Id        uuid.UUID
+		CountryId uuid.UUID
+		Alias     string
+		Source    string
+		CreatedBy uuid.UUID
+		CreatedAt time.Time
+		UpdatedBy uuid.NullUUID
+		UpdatedAt pq.NullTime
+	}
+
+	CountryAliasResponse struct {
+		Id        uuid.UUID `json:"id"`
+		CountryId uuid.UUID `json:"country_id"`
+		Alias     string    `json:"alias"`
+		Source    string    `json:"source"`
+		CreatedBy uuid.UUID `json:"created_by"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedBy uuid.UUID `json:"updated_by"`
+		UpdatedAt time.Time `json:"updated_at"`
+	}
+)
+
+func (s CountryAliasModel) Response() CountryAliasResponse {
+
+	return CountryAliasResponse{
+		Id:        s.Id,
+		CountryId: s.CountryId,
+		Alias:     s.Alias,
+		Source:    s.Source,
+		CreatedBy: s.CreatedBy,
+		CreatedAt: s.CreatedAt,
+		UpdatedBy: s.UpdatedBy.UUID,
+		UpdatedAt: s.UpdatedAt.Time,
+	}
+
+}
+
+func GetOneCountryAlias(ctx context.Context, db helpers.Querier, id uuid.UUID) (CountryAliasModel, error) {
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			country_id,
+			alias,
+			source,
+			created_by,
+			created_at,
+			updated_by,
+			updated_at
+		FROM
+			country_alias
+		WHERE
+			id = $1
+	`)
+
+	var alias CountryAliasModel
+	err := db.QueryRowContext(ctx, query, id).Scan(
+		&alias.Id,
+		&alias.CountryId,
+		&alias.Alias,
+		&alias.Source,
+		&alias.CreatedBy,
+		&alias.CreatedAt,
+		&alias.UpdatedBy,
+		&alias.UpdatedAt,
+	)
+
+	if err != nil {
+		return CountryAliasModel{}, err
+	}
+
+	return alias, nil
+
+}
+
+func GetAllCountryAliasByCountry(ctx context.Context, db helpers.Querier, countryId uuid.UUID) (
+	[]CountryAliasModel, error) {
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			country_id,
+			alias,
+			source,
+			created_by,
+			created_at,
+			updated_by,
+			updated_at
+		FROM
+			country_alias
+		WHERE
+			country_id = $1
+		ORDER BY
+			alias
+	`)
+
+	return queryCountryAliases(ctx, db, query, countryId)
+}
+
+// GetAllCountryAliasBySource returns the aliases that apply to source, which are
+// the ones recorded for it plus the ones recorded without a source.
+func GetAllCountryAliasBySource(ctx context.Context, db helpers.Querier, source string) ([]CountryAliasModel, error) {
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			country_id,
+			alias,
+			source,
+			created_by,
+			created_at,
+			updated_by,
+			updated_at
+		FROM
+			country_alias
+		WHERE
+			source = $1 OR source = ''
+	`)
+
+	return queryCountryAliases(ctx, db, query, source)
+}
+
+func queryCountryAliases(ctx context.Context, db helpers.Querier, query string, args ...interface{}) (
+	[]CountryAliasModel, error) {
+
+	rows, err := db.QueryContext(ctx, query, args...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var aliases []CountryAliasModel
+	for rows.Next() {
+		var alias CountryAliasModel
+
+		rows.Scan(
+			&alias.Id,
+			&alias.CountryId,
+			&alias.Alias,
+			&alias.Source,
+			&alias.CreatedBy,
+			&alias.CreatedAt,
+			&alias.UpdatedBy,
+			&alias.UpdatedAt,
+		)
+
+		aliases = append(aliases, alias)
+	}
+
+	return aliases, nil
+}
+
+func (s *CountryAliasModel) Insert(ctx context.Context, db helpers.Querier) error {
+
+	query := fmt.Sprintf(`
+		INSERT INTO country_alias(
+			country_id,
+			alias,
+			source,
+			created_by,
+			created_at
+		)VALUES(
+			$1,$2,$3,$4,now())
+		RETURNING
+			id, created_at
+	`)
+
+	err := db.QueryRowContext(ctx, query,
+		s.CountryId, s.Alias, s.Source, s.CreatedBy).Scan(
+		&s.Id, &s.CreatedAt,
+	)
+
+	if err != nil {
+		return duplicateError(err, "alias %q already exists for source %q", s.Alias, s.Source)
+	}
+
+	return nil
+
+}
+
+// Upsert inserts the alias unless the same alias is already recorded for the
+// source, whichever country it points to.
+func (s *CountryAliasModel) Upsert(ctx context.Context, db helpers.Querier) (UpsertResult, error) {
+
+	query := fmt.Sprintf(`
+		INSERT INTO country_alias(
+			country_id,
+			alias,
+			source,
+			created_by,
+			created_at
+		)VALUES(
+			$1,$2,$3,$4,now())
+		ON CONFLICT ((LOWER(alias)), source)
+		DO NOTHING
+		RETURNING
+			id, created_at
+	`)
+
+	err := db.QueryRowContext(ctx, query,
+		s.CountryId, s.Alias, s.Source, s.CreatedBy).Scan(
+		&s.Id, &s.CreatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return UpsertUnchanged, nil
+	}
+
+	if err != nil {
+		return UpsertUnchanged, err
+	}
+
+	return UpsertInserted, nil
+
+}
+
+func (s *CountryAliasModel) Delete(ctx context.Context, db helpers.Querier) error {
+
+	query := fmt.Sprintf(`
+		DELETE FROM
+			country_alias
+		WHERE
+			id = $1
+	`)
+
+	_, err := db.ExecContext(ctx, query, s.Id)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+
+}
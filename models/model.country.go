@@ -16,6 +16,8 @@ type (
 		ContinentId uuid.UUID
 		Name        string
 		Code        string
+		Alpha3      string
+		NumericCode string
 		CreatedBy   uuid.UUID
 		CreatedAt   time.Time
 		UpdatedBy   uuid.NullUUID
@@ -23,18 +25,56 @@ type (
 	}
 
 	CountryResponse struct {
-		Id        uuid.UUID         `json:"id"`
-		Continent ContinentResponse `json:"continent"`
-		Name      string            `json:"name"`
-		Code      string            `json:"code"`
-		CreatedBy uuid.UUID         `json:"created_by"`
-		CreatedAt time.Time         `json:"created_at"`
-		UpdatedBy uuid.UUID         `json:"updated_by"`
-		UpdatedAt time.Time         `json:"updated_at"`
+		Id          uuid.UUID         `json:"id"`
+		Continent   ContinentResponse `json:"continent"`
+		Name        string            `json:"name"`
+		Code        string            `json:"code"`
+		Alpha3      string            `json:"alpha3"`
+		NumericCode string            `json:"numeric_code"`
+		CreatedBy   uuid.UUID         `json:"created_by"`
+		CreatedAt   time.Time         `json:"created_at"`
+		UpdatedBy   uuid.UUID         `json:"updated_by"`
+		UpdatedAt   time.Time         `json:"updated_at"`
+	}
+
+	// CountryDetailModel is a country read along with its continent, so lists can
+	// build their responses without a query per row.
+	CountryDetailModel struct {
+		Country   CountryModel
+		Continent ContinentModel
 	}
 )
 
-func (s CountryModel) Response(ctx context.Context, db *sql.DB, logger *helpers.Logger) (CountryResponse, error) {
+// countryDetailColumns selects a CountryDetailModel from country c joined with
+// continent co, in the order of CountryDetailModel.fields.
+const countryDetailColumns = `
+			c.id,
+			c.continent_id,
+			c.name,
+			c.code,
+			c.alpha3,
+			c.numeric_code,
+			c.created_by,
+			c.created_at,
+			c.updated_by,
+			c.updated_at,
+			co.id,
+			co.name,
+			co.code,
+			co.created_by,
+			co.created_at,
+			co.updated_by,
+			co.updated_at`
+
+// countryDetailFrom joins country c with continent co.
+const countryDetailFrom = `
+			country c
+		INNER JOIN
+			continent co
+		ON
+			c.continent_id = co.id`
+
+func (s CountryModel) Response(ctx context.Context, db helpers.Querier, logger *helpers.Logger) (CountryResponse, error) {
 
 	continent, err := GetOneContinent(ctx, db, s.ContinentId)
 
@@ -42,19 +82,55 @@ func (s CountryModel) Response(ctx context.Context, db *sql.DB, logger *helpers.
 		return CountryResponse{}, err
 	}
 
+	return CountryDetailModel{Country: s, Continent: continent}.Response(), nil
+}
+
+// ISOCode returns the alpha-2 code of the country, which map formats locate it
+// by.
+func (s CountryResponse) ISOCode() string {
+	return s.Code
+}
+
+func (s CountryDetailModel) Response() CountryResponse {
+
 	return CountryResponse{
-		Id:        s.Id,
-		Continent: continent.Response(),
-		Name:      s.Name,
-		Code:      s.Code,
-		CreatedBy: s.CreatedBy,
-		CreatedAt: s.CreatedAt,
-		UpdatedBy: s.UpdatedBy.UUID,
-		UpdatedAt: s.UpdatedAt.Time,
-	}, nil
+		Id:          s.Country.Id,
+		Continent:   s.Continent.Response(),
+		Name:        s.Country.Name,
+		Code:        s.Country.Code,
+		Alpha3:      s.Country.Alpha3,
+		NumericCode: s.Country.NumericCode,
+		CreatedBy:   s.Country.CreatedBy,
+		CreatedAt:   s.Country.CreatedAt,
+		UpdatedBy:   s.Country.UpdatedBy.UUID,
+		UpdatedAt:   s.Country.UpdatedAt.Time,
+	}
+}
+
+func (s *CountryDetailModel) fields() []interface{} {
+
+	return []interface{}{
+		&s.Country.Id,
+		&s.Country.ContinentId,
+		&s.Country.Name,
+		&s.Country.Code,
+		&s.Country.Alpha3,
+		&s.Country.NumericCode,
+		&s.Country.CreatedBy,
+		&s.Country.CreatedAt,
+		&s.Country.UpdatedBy,
+		&s.Country.UpdatedAt,
+		&s.Continent.Id,
+		&s.Continent.Name,
+		&s.Continent.Code,
+		&s.Continent.CreatedBy,
+		&s.Continent.CreatedAt,
+		&s.Continent.UpdatedBy,
+		&s.Continent.UpdatedAt,
+	}
 }
 
-func GetOneCountry(ctx context.Context, db *sql.DB, id uuid.UUID) (CountryModel, error) {
+func GetOneCountry(ctx context.Context, db helpers.Querier, id uuid.UUID) (CountryModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -62,6 +138,8 @@ func GetOneCountry(ctx context.Context, db *sql.DB, id uuid.UUID) (CountryModel,
 			continent_id,
 			name,	
 			code,
+			alpha3,
+			numeric_code,
 			created_by,
 			created_at,
 			updated_by,
@@ -78,6 +156,8 @@ func GetOneCountry(ctx context.Context, db *sql.DB, id uuid.UUID) (CountryModel,
 		&country.ContinentId,
 		&country.Name,
 		&country.Code,
+		&country.Alpha3,
+		&country.NumericCode,
 		&country.CreatedBy,
 		&country.CreatedAt,
 		&country.UpdatedBy,
@@ -92,7 +172,49 @@ func GetOneCountry(ctx context.Context, db *sql.DB, id uuid.UUID) (CountryModel,
 
 }
 
-func GetAllCountry(ctx context.Context, db *sql.DB) ([]CountryModel, error) {
+func GetOneCountryByName(ctx context.Context, db helpers.Querier, name string) (CountryModel, error) {
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			continent_id,
+			name,	
+			code,
+			alpha3,
+			numeric_code,
+			created_by,
+			created_at,
+			updated_by,
+			updated_at
+		FROM 
+			country
+		WHERE 
+			LOWER(name) = LOWER($1)
+	`)
+
+	var country CountryModel
+	err := db.QueryRowContext(ctx, query, name).Scan(
+		&country.Id,
+		&country.ContinentId,
+		&country.Name,
+		&country.Code,
+		&country.Alpha3,
+		&country.NumericCode,
+		&country.CreatedBy,
+		&country.CreatedAt,
+		&country.UpdatedBy,
+		&country.UpdatedAt,
+	)
+
+	if err != nil {
+		return CountryModel{}, err
+	}
+
+	return country, nil
+
+}
+
+func GetAllCountry(ctx context.Context, db helpers.Querier) ([]CountryModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -100,6 +222,8 @@ func GetAllCountry(ctx context.Context, db *sql.DB) ([]CountryModel, error) {
 			continent_id,
 			name,
 			code,
+			alpha3,
+			numeric_code,
 			created_by,
 			created_at,
 			updated_by,
@@ -125,6 +249,8 @@ func GetAllCountry(ctx context.Context, db *sql.DB) ([]CountryModel, error) {
 			&country.ContinentId,
 			&country.Name,
 			&country.Code,
+			&country.Alpha3,
+			&country.NumericCode,
 			&country.CreatedBy,
 			&country.CreatedAt,
 			&country.UpdatedBy,
@@ -137,33 +263,36 @@ func GetAllCountry(ctx context.Context, db *sql.DB) ([]CountryModel, error) {
 	return countries, nil
 }
 
-func GetAllCountries(ctx context.Context, db *sql.DB, filter helpers.Filter) ([]CountryModel, error) {
+// countrySortColumns are the sorts of GetAllCountries.
+var countrySortColumns = map[string]string{
+	SortName: "c.name",
+}
+
+func countryBuilder(filter helpers.Filter) *helpers.QueryBuilder {
 
-	var searchQuery string
+	builder := helpers.NewQueryBuilder(filter)
+	builder.Search("c.name")
+	builder.Range(noRangeColumns)
+	builder.OrderBy(countrySortColumns, SortName, "c.id")
+
+	return builder
+}
 
-	if filter.Search != "" {
-		searchQuery = fmt.Sprintf(`WHERE LOWER(name) LIKE LOWER('%%%s%%')`, filter.Search)
+func GetAllCountries(ctx context.Context, db helpers.Querier, filter helpers.Filter) ([]CountryDetailModel, error) {
+
+	clauses, args, err := countryBuilder(filter).Build()
+
+	if err != nil {
+		return nil, err
 	}
 
 	query := fmt.Sprintf(`
-		SELECT
-			id,
-			continent_id,
-			name,
-			code,
-			created_by,
-			created_at,
-			updated_by,
-			updated_at
-		FROM 
-			country
-		%s 
-		ORDER BY
-			name  %s
-		LIMIT $1 OFFSET $2`,
-		searchQuery, filter.Dir)
+		SELECT %s
+		FROM %s
+		%s`,
+		countryDetailColumns, countryDetailFrom, clauses)
 
-	rows, err := db.QueryContext(ctx, query, filter.Limit, filter.Offset)
+	rows, err := db.QueryContext(ctx, query, args...)
 
 	if err != nil {
 		return nil, err
@@ -171,44 +300,51 @@ func GetAllCountries(ctx context.Context, db *sql.DB, filter helpers.Filter) ([]
 
 	defer rows.Close()
 
-	var countries []CountryModel
+	var countries []CountryDetailModel
 	for rows.Next() {
-		var country CountryModel
+		var country CountryDetailModel
 
-		rows.Scan(
-			&country.Id,
-			&country.ContinentId,
-			&country.Name,
-			&country.Code,
-			&country.CreatedBy,
-			&country.CreatedAt,
-			&country.UpdatedBy,
-			&country.UpdatedAt,
-		)
+		err := rows.Scan(country.fields()...)
+
+		if err != nil {
+			return nil, err
+		}
 
 		countries = append(countries, country)
 	}
 
-	return countries, nil
+	return countries, rows.Err()
+}
+
+func CountCountries(ctx context.Context, db helpers.Querier, filter helpers.Filter) (int, error) {
+	return countRows(ctx, db, countryDetailFrom, countryBuilder(filter))
+}
+
+// Cursor returns the cursor of the page of a list sorted as filter asks that
+// ends with s.
+func (s CountryDetailModel) Cursor(filter helpers.Filter) string {
+	return helpers.NewCursor(filter, &s.Country.Name, s.Country.Id.String())
 }
 
-func (s *CountryModel) Insert(ctx context.Context, db *sql.DB) error {
+func (s *CountryModel) Insert(ctx context.Context, db helpers.Querier) error {
 
 	query := fmt.Sprintf(`
 		INSERT INTO country(
 			continent_id,
 			name,
 			code,
+			alpha3,
+			numeric_code,
 			created_by,
 			created_at
 		)VALUES(
-			$1,$2,$3,$4,now())
+			$1,$2,$3,$4,$5,$6,now())
 		RETURNING
 			id, created_at
 	`)
 
 	err := db.QueryRowContext(ctx, query,
-		s.ContinentId, s.Name, s.Code, s.CreatedBy).Scan(
+		s.ContinentId, s.Name, s.Code, s.Alpha3, s.NumericCode, s.CreatedBy).Scan(
 		&s.Id, &s.CreatedAt,
 	)
 
@@ -219,3 +355,100 @@ func (s *CountryModel) Insert(ctx context.Context, db *sql.DB) error {
 	return nil
 
 }
+
+// GetOneCountryByCode returns the country of an ISO 3166-1 alpha-2 or alpha-3
+// code, ignoring case.
+func GetOneCountryByCode(ctx context.Context, db helpers.Querier, code string) (CountryModel, error) {
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			continent_id,
+			name,	
+			code,
+			alpha3,
+			numeric_code,
+			created_by,
+			created_at,
+			updated_by,
+			updated_at
+		FROM 
+			country
+		WHERE 
+			code = UPPER($1) OR alpha3 = UPPER($1)
+	`)
+
+	var country CountryModel
+	err := db.QueryRowContext(ctx, query, code).Scan(
+		&country.Id,
+		&country.ContinentId,
+		&country.Name,
+		&country.Code,
+		&country.Alpha3,
+		&country.NumericCode,
+		&country.CreatedBy,
+		&country.CreatedAt,
+		&country.UpdatedBy,
+		&country.UpdatedAt,
+	)
+
+	if err != nil {
+		return CountryModel{}, err
+	}
+
+	return country, nil
+
+}
+
+// Upsert inserts the country or, when one with the same code exists, updates its
+// continent and ISO codes. The name of an existing country is kept as it is what
+// the scrapper matches on, and a row that is already up to date is left alone.
+func (s *CountryModel) Upsert(ctx context.Context, db helpers.Querier) (UpsertResult, error) {
+
+	query := fmt.Sprintf(`
+		INSERT INTO country(
+			continent_id,
+			name,
+			code,
+			alpha3,
+			numeric_code,
+			created_by,
+			created_at
+		)VALUES(
+			$1,$2,$3,$4,$5,$6,now())
+		ON CONFLICT (code)
+		DO UPDATE SET
+			continent_id=EXCLUDED.continent_id,
+			alpha3=EXCLUDED.alpha3,
+			numeric_code=EXCLUDED.numeric_code,
+			updated_at=NOW(),
+			updated_by=EXCLUDED.created_by
+		WHERE
+			(country.continent_id, country.alpha3, country.numeric_code)
+			IS DISTINCT FROM
+			(EXCLUDED.continent_id, EXCLUDED.alpha3, EXCLUDED.numeric_code)
+		RETURNING
+			id, name, created_at, created_by, updated_at, (xmax = 0)
+	`)
+
+	var inserted bool
+	err := db.QueryRowContext(ctx, query,
+		s.ContinentId, s.Name, s.Code, s.Alpha3, s.NumericCode, s.CreatedBy).Scan(
+		&s.Id, &s.Name, &s.CreatedAt, &s.CreatedBy, &s.UpdatedAt, &inserted,
+	)
+
+	if err == sql.ErrNoRows {
+		*s, err = GetOneCountryByCode(ctx, db, s.Code)
+		if err != nil {
+			return UpsertUnchanged, err
+		}
+		return UpsertUnchanged, nil
+	}
+
+	if err != nil {
+		return UpsertUnchanged, err
+	}
+
+	return upsertResult(inserted), nil
+
+}
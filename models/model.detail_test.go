@@ -0,0 +1,31 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+// The joined list queries scan into fields(), which has to follow the column
+// lists exactly.
+func TestDetailColumns(t *testing.T) {
+
+	var (
+		country CountryDetailModel
+		corona  CoronaDetailModel
+		user    UserDetailModel
+	)
+
+	for _, tc := range []struct {
+		name    string
+		columns string
+		fields  []interface{}
+	}{
+		{"country", countryDetailColumns, country.fields()},
+		{"corona", coronaDetailColumns, corona.fields()},
+		{"user", userDetailColumns, user.fields()},
+	} {
+		if got := len(strings.Split(tc.columns, ",")); got != len(tc.fields) {
+			t.Errorf("%s: %d columns for %d fields", tc.name, got, len(tc.fields))
+		}
+	}
+}
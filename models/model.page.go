@@ -0,0 +1,46 @@
+package models
+
+import (
+	"context"
+	"corona/helpers"
+	"fmt"
+	"strconv"
+)
+
+// noRangeColumns are the range columns of the lists that cannot be bounded, so
+// that QueryBuilder.Range rejects their bounds rather than ignoring them.
+var noRangeColumns = map[string]string{}
+
+// countRows returns how many rows of from the filter of builder keeps, whatever
+// the page.
+func countRows(ctx context.Context, db helpers.Querier, from string, builder *helpers.QueryBuilder) (int, error) {
+
+	clause, args, err := builder.Count()
+
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM %s
+		%s`, from, clause)
+
+	var total int
+	err = db.QueryRowContext(ctx, query, args...).Scan(&total)
+
+	return total, err
+}
+
+// cursorValue formats a sort value the way Postgres reads it back for any of
+// the numeric columns, nil staying NULL.
+func cursorValue(value *float64) *string {
+
+	if value == nil {
+		return nil
+	}
+
+	formatted := strconv.FormatFloat(*value, 'f', -1, 64)
+
+	return &formatted
+}
@@ -3,7 +3,6 @@ package models
 import (
 	"context"
 	"corona/helpers"
-	"database/sql"
 	"fmt"
 	"github.com/lib/pq"
 	uuid "github.com/satori/go.uuid"
@@ -32,9 +31,16 @@ type (
 		UpdatedBy    uuid.UUID    `json:"updated_by"`
 		UpdatedAt    time.Time    `json:"updated_at"`
 	}
+
+	// RateLimitDetailModel is a rate limit read along with its user and
+	// subscription, so lists can build their responses without a query per row.
+	RateLimitDetailModel struct {
+		RateLimit RateLimitModel
+		User      UserDetailModel
+	}
 )
 
-func (r RateLimitModel) Response(ctx context.Context, db *sql.DB, logger *helpers.Logger) (RateLimitResponse, error) {
+func (r RateLimitModel) Response(ctx context.Context, db helpers.Querier, logger *helpers.Logger) (RateLimitResponse, error) {
 
 	user, err := GetOneUser(ctx, db, r.UserId)
 	if err != nil {
@@ -42,26 +48,35 @@ func (r RateLimitModel) Response(ctx context.Context, db *sql.DB, logger *helper
 		return RateLimitResponse{}, err
 	}
 
-	userResponse, err := user.Response(ctx, db, logger)
+	subscription, err := GetOneSubscription(ctx, db, user.SubscriptionId)
 	if err != nil {
-		logger.Err.Printf(`model.rate.limit.go/user.Response/%v`, err)
+		logger.Err.Printf(`model.rate.limit.go/GetOneSubscription/%v`, err)
 		return RateLimitResponse{}, err
 	}
 
+	return RateLimitDetailModel{
+		RateLimit: r,
+		User:      UserDetailModel{User: user, Subscription: subscription},
+	}.Response(), nil
+
+}
+
+func (r RateLimitDetailModel) Response() RateLimitResponse {
+
 	return RateLimitResponse{
-		Id:           r.Id,
-		User:         userResponse,
-		TotalRequest: r.TotalRequest,
-		IsDelete:     r.IsDelete,
-		CreatedBy:    r.CreatedBy,
-		CreatedAt:    r.CreatedAt,
-		UpdatedBy:    r.UpdatedBy.UUID,
-		UpdatedAt:    r.UpdatedAt.Time,
-	}, nil
+		Id:           r.RateLimit.Id,
+		User:         r.User.Response(),
+		TotalRequest: r.RateLimit.TotalRequest,
+		IsDelete:     r.RateLimit.IsDelete,
+		CreatedBy:    r.RateLimit.CreatedBy,
+		CreatedAt:    r.RateLimit.CreatedAt,
+		UpdatedBy:    r.RateLimit.UpdatedBy.UUID,
+		UpdatedAt:    r.RateLimit.UpdatedAt.Time,
+	}
 
 }
 
-func GetOneRateLimit(ctx context.Context, db *sql.DB, id uuid.UUID) (RateLimitModel, error) {
+func GetOneRateLimit(ctx context.Context, db helpers.Querier, id uuid.UUID) (RateLimitModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -99,7 +114,7 @@ func GetOneRateLimit(ctx context.Context, db *sql.DB, id uuid.UUID) (RateLimitMo
 
 }
 
-func GetOneRateLimitByUserId(ctx context.Context, db *sql.DB, userId uuid.UUID) (RateLimitModel, error) {
+func GetOneRateLimitByUserId(ctx context.Context, db helpers.Querier, userId uuid.UUID) (RateLimitModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -137,21 +152,29 @@ func GetOneRateLimitByUserId(ctx context.Context, db *sql.DB, userId uuid.UUID)
 
 }
 
-func GetAllRateLimit(ctx context.Context, db *sql.DB) ([]RateLimitModel, error) {
+func GetAllRateLimit(ctx context.Context, db helpers.Querier) ([]RateLimitDetailModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
-			id,
-			user_id,
-			total_request,
-			is_delete,
-			created_by,
-			created_at,
-			updated_by,
-			updated_at
+			r.id,
+			r.user_id,
+			r.total_request,
+			r.is_delete,
+			r.created_by,
+			r.created_at,
+			r.updated_by,
+			r.updated_at,%s
 		FROM 
-			rate_limit
-	`)
+			rate_limit r
+		INNER JOIN
+			"user" u
+		ON
+			r.user_id = u.id
+		INNER JOIN
+			subscription s
+		ON
+			u.subscription_id = s.id
+	`, userDetailColumns)
 
 	rows, err := db.QueryContext(ctx, query)
 
@@ -161,28 +184,32 @@ func GetAllRateLimit(ctx context.Context, db *sql.DB) ([]RateLimitModel, error)
 
 	defer rows.Close()
 
-	var limits []RateLimitModel
+	var limits []RateLimitDetailModel
 	for rows.Next() {
-		var limit RateLimitModel
-
-		rows.Scan(
-			&limit.Id,
-			&limit.UserId,
-			&limit.TotalRequest,
-			&limit.IsDelete,
-			&limit.CreatedBy,
-			&limit.CreatedAt,
-			&limit.UpdatedBy,
-			&limit.UpdatedAt,
-		)
+		var limit RateLimitDetailModel
+
+		err := rows.Scan(append([]interface{}{
+			&limit.RateLimit.Id,
+			&limit.RateLimit.UserId,
+			&limit.RateLimit.TotalRequest,
+			&limit.RateLimit.IsDelete,
+			&limit.RateLimit.CreatedBy,
+			&limit.RateLimit.CreatedAt,
+			&limit.RateLimit.UpdatedBy,
+			&limit.RateLimit.UpdatedAt,
+		}, limit.User.fields()...)...)
+
+		if err != nil {
+			return nil, err
+		}
 
 		limits = append(limits, limit)
 	}
 
-	return limits, nil
+	return limits, rows.Err()
 }
 
-func (r *RateLimitModel) Insert(ctx context.Context, db *sql.DB) error {
+func (r *RateLimitModel) Insert(ctx context.Context, db helpers.Querier) error {
 
 	query := fmt.Sprintf(`
 		INSERT INTO rate_limit(
@@ -209,7 +236,7 @@ func (r *RateLimitModel) Insert(ctx context.Context, db *sql.DB) error {
 
 }
 
-func (r *RateLimitModel) Update(ctx context.Context, db *sql.DB) error {
+func (r *RateLimitModel) Update(ctx context.Context, db helpers.Querier) error {
 
 	query := fmt.Sprintf(`
 		UPDATE rate_limit
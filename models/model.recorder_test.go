@@ -0,0 +1,181 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"flag"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// The Postgres queries are checked against golden files in testdata, run through
+// a driver that records them instead of reaching a database. go test -update
+// rewrites the files.
+var update = flag.Bool("update", false, "rewrite the golden queries in testdata")
+
+type (
+	// recorder answers every query of a test with rows and records the queries
+	// and the arguments they were sent, as the driver converted them.
+	recorder struct {
+		columns []string
+		rows    [][]driver.Value
+		queries []string
+		args    [][]driver.Value
+	}
+
+	recorderDriver struct{}
+
+	recorderConn struct {
+		r *recorder
+	}
+
+	recorderRows struct {
+		columns []string
+		rows    [][]driver.Value
+	}
+)
+
+var (
+	recordersMu sync.Mutex
+	recorders   = make(map[string]*recorder)
+)
+
+func init() {
+	sql.Register("recorder", recorderDriver{})
+}
+
+// newRecorder returns a database answering every query with rows of columns.
+func newRecorder(t *testing.T, columns []string, rows ...[]driver.Value) (*sql.DB, *recorder) {
+	t.Helper()
+
+	r := &recorder{columns: columns, rows: rows}
+
+	recordersMu.Lock()
+	recorders[t.Name()] = r
+	recordersMu.Unlock()
+
+	db, err := sql.Open("recorder", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		db.Close()
+		recordersMu.Lock()
+		delete(recorders, t.Name())
+		recordersMu.Unlock()
+	})
+
+	return db, r
+}
+
+// checkQuery compares the only query r recorded, whitespace aside, with the
+// golden file name, and its arguments with args.
+func (r *recorder) checkQuery(t *testing.T, name string, args ...driver.Value) {
+	t.Helper()
+
+	if len(r.queries) != 1 {
+		t.Fatalf("recorded %d queries, want 1", len(r.queries))
+	}
+
+	query := strings.Join(strings.Fields(r.queries[0]), " ") + "\n"
+	golden := filepath.Join("testdata", name+".sql")
+
+	if *update {
+		if err := ioutil.WriteFile(golden, []byte(query), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if query != string(want) {
+		t.Errorf("query\n%s\nwant\n%s", query, want)
+	}
+
+	if !reflect.DeepEqual(r.args[0], args) {
+		t.Errorf("args %#v\nwant %#v", r.args[0], args)
+	}
+}
+
+func (recorderDriver) Open(name string) (driver.Conn, error) {
+
+	recordersMu.Lock()
+	defer recordersMu.Unlock()
+
+	r, ok := recorders[name]
+	if !ok {
+		return nil, errors.Errorf("no recorder %q", name)
+	}
+
+	return &recorderConn{r: r}, nil
+}
+
+func (c *recorderConn) record(query string, args []driver.NamedValue) {
+
+	values := make([]driver.Value, 0, len(args))
+	for _, arg := range args {
+		values = append(values, arg.Value)
+	}
+
+	c.r.queries = append(c.r.queries, query)
+	c.r.args = append(c.r.args, values)
+}
+
+func (c *recorderConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (
+	driver.Rows, error) {
+
+	c.record(query, args)
+
+	return &recorderRows{columns: c.r.columns, rows: c.r.rows}, nil
+}
+
+func (c *recorderConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (
+	driver.Result, error) {
+
+	c.record(query, args)
+
+	return driver.RowsAffected(len(c.r.rows)), nil
+}
+
+func (c *recorderConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("prepared statements are not recorded")
+}
+
+func (c *recorderConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not recorded")
+}
+
+func (c *recorderConn) Close() error {
+	return nil
+}
+
+func (r *recorderRows) Columns() []string {
+	return r.columns
+}
+
+func (r *recorderRows) Close() error {
+	return nil
+}
+
+func (r *recorderRows) Next(dest []driver.Value) error {
+
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
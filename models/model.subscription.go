@@ -2,7 +2,7 @@ package models
 
 import (
 	"context"
-	"database/sql"
+	"corona/helpers"
 	"fmt"
 	"github.com/lib/pq"
 	uuid "github.com/satori/go.uuid"
@@ -48,7 +48,7 @@ func (s SubscriptionModel) Response() SubscriptionResponse {
 
 }
 
-func GetOneSubscription(ctx context.Context, db *sql.DB, id uuid.UUID) (SubscriptionModel, error) {
+func GetOneSubscription(ctx context.Context, db helpers.Querier, id uuid.UUID) (SubscriptionModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -86,7 +86,7 @@ func GetOneSubscription(ctx context.Context, db *sql.DB, id uuid.UUID) (Subscrip
 
 }
 
-func GetAllSubscription(ctx context.Context, db *sql.DB) ([]SubscriptionModel, error) {
+func GetAllSubscription(ctx context.Context, db helpers.Querier) ([]SubscriptionModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -131,7 +131,7 @@ func GetAllSubscription(ctx context.Context, db *sql.DB) ([]SubscriptionModel, e
 
 }
 
-func (s *SubscriptionModel) Insert(ctx context.Context, db *sql.DB) error {
+func (s *SubscriptionModel) Insert(ctx context.Context, db helpers.Querier) error {
 
 	query := fmt.Sprintf(`
 		INSERT INTO subscription(
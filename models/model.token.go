@@ -3,7 +3,6 @@ package models
 import (
 	"context"
 	"corona/helpers"
-	"database/sql"
 	"fmt"
 	"github.com/lib/pq"
 	uuid "github.com/satori/go.uuid"
@@ -34,9 +33,16 @@ type (
 		UpdatedBy uuid.UUID    `json:"updated_by"`
 		UpdatedAt time.Time    `json:"updated_at"`
 	}
+
+	// TokenDetailModel is a token read along with its user and subscription, so
+	// lists can build their responses without a query per row.
+	TokenDetailModel struct {
+		Token TokenModel
+		User  UserDetailModel
+	}
 )
 
-func (t TokenModel) Response(ctx context.Context, db *sql.DB, logger *helpers.Logger) (TokenResponse, error) {
+func (t TokenModel) Response(ctx context.Context, db helpers.Querier, logger *helpers.Logger) (TokenResponse, error) {
 
 	user, err := GetOneUser(ctx, db, t.UserId)
 
@@ -45,28 +51,37 @@ func (t TokenModel) Response(ctx context.Context, db *sql.DB, logger *helpers.Lo
 		return TokenResponse{}, err
 	}
 
-	userResponse, err := user.Response(ctx, db, logger)
+	subscription, err := GetOneSubscription(ctx, db, user.SubscriptionId)
 
 	if err != nil {
-		logger.Err.Printf(`model.token.go/user.Response/%v`, err)
+		logger.Err.Printf(`model.token.go/GetOneSubscription/%v`, err)
 		return TokenResponse{}, err
 	}
 
+	return TokenDetailModel{
+		Token: t,
+		User:  UserDetailModel{User: user, Subscription: subscription},
+	}.Response(), nil
+
+}
+
+func (t TokenDetailModel) Response() TokenResponse {
+
 	return TokenResponse{
-		Id:        t.Id,
-		User:      userResponse,
-		TokenKey:  t.TokenKey,
-		ExpiredAt: t.ExpiredAt,
-		IsActive:  t.IsActive,
-		CreatedBy: t.CreatedBy,
-		CreatedAt: t.CreatedAt,
-		UpdatedBy: t.UpdatedBy.UUID,
-		UpdatedAt: t.UpdatedAt.Time,
-	}, nil
+		Id:        t.Token.Id,
+		User:      t.User.Response(),
+		TokenKey:  t.Token.TokenKey,
+		ExpiredAt: t.Token.ExpiredAt,
+		IsActive:  t.Token.IsActive,
+		CreatedBy: t.Token.CreatedBy,
+		CreatedAt: t.Token.CreatedAt,
+		UpdatedBy: t.Token.UpdatedBy.UUID,
+		UpdatedAt: t.Token.UpdatedAt.Time,
+	}
 
 }
 
-func GetOneToken(ctx context.Context, db *sql.DB, id uuid.UUID) (TokenModel, error) {
+func GetOneToken(ctx context.Context, db helpers.Querier, id uuid.UUID) (TokenModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -106,7 +121,7 @@ func GetOneToken(ctx context.Context, db *sql.DB, id uuid.UUID) (TokenModel, err
 
 }
 
-func GetOneTokenByTokenKey(ctx context.Context, db *sql.DB, tokenKey string) (TokenModel, error) {
+func GetOneTokenByTokenKey(ctx context.Context, db helpers.Querier, tokenKey string) (TokenModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -146,22 +161,30 @@ func GetOneTokenByTokenKey(ctx context.Context, db *sql.DB, tokenKey string) (To
 
 }
 
-func GetAllToken(ctx context.Context, db *sql.DB) ([]TokenModel, error) {
+func GetAllToken(ctx context.Context, db helpers.Querier) ([]TokenDetailModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
-			id,
-			user_id,
-			token_key,
-			expired_at,
-			is_active,
-			created_by,
-			created_at,
-			updated_by,
-			updated_at
+			t.id,
+			t.user_id,
+			t.token_key,
+			t.expired_at,
+			t.is_active,
+			t.created_by,
+			t.created_at,
+			t.updated_by,
+			t.updated_at,%s
 		FROM 
-			token
-		`)
+			token t
+		INNER JOIN
+			"user" u
+		ON
+			t.user_id = u.id
+		INNER JOIN
+			subscription s
+		ON
+			u.subscription_id = s.id
+		`, userDetailColumns)
 
 	rows, err := db.QueryContext(ctx, query)
 
@@ -171,30 +194,34 @@ func GetAllToken(ctx context.Context, db *sql.DB) ([]TokenModel, error) {
 
 	defer rows.Close()
 
-	var tokens []TokenModel
+	var tokens []TokenDetailModel
 	for rows.Next() {
-		var token TokenModel
-
-		rows.Scan(
-			&token.Id,
-			&token.UserId,
-			&token.TokenKey,
-			&token.ExpiredAt,
-			&token.IsActive,
-			&token.CreatedBy,
-			&token.CreatedAt,
-			&token.UpdatedBy,
-			&token.UpdatedAt,
-		)
+		var token TokenDetailModel
+
+		err := rows.Scan(append([]interface{}{
+			&token.Token.Id,
+			&token.Token.UserId,
+			&token.Token.TokenKey,
+			&token.Token.ExpiredAt,
+			&token.Token.IsActive,
+			&token.Token.CreatedBy,
+			&token.Token.CreatedAt,
+			&token.Token.UpdatedBy,
+			&token.Token.UpdatedAt,
+		}, token.User.fields()...)...)
+
+		if err != nil {
+			return nil, err
+		}
 
 		tokens = append(tokens, token)
 	}
 
-	return tokens, nil
+	return tokens, rows.Err()
 
 }
 
-func (t *TokenModel) Insert(ctx context.Context, db *sql.DB) error {
+func (t *TokenModel) Insert(ctx context.Context, db helpers.Querier) error {
 
 	query := fmt.Sprintf(`
 		INSERT INTO token(
@@ -0,0 +1,243 @@
+package models
+
+import (
+	"context"
+	"corona/helpers"
+	"fmt"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+type (
+	UnmatchedScrapeRowModel struct {
+		Id         uuid.UUID
+		Source     string
+		Name       string
+		Cells      []string
+		ScrapedAt  time.Time
+		CountryId  uuid.NullUUID
+		ResolvedBy uuid.NullUUID
+		ResolvedAt pq.NullTime
+		CreatedBy  uuid.UUID
+		CreatedAt  time.Time
+		UpdatedBy  uuid.NullUUID
+		UpdatedAt  pq.NullTime
+	}
+
+	UnmatchedScrapeRowResponse struct {
+		Id         uuid.UUID  `json:"id"`
+		Source     string     `json:"source"`
+		Name       string     `json:"name"`
+		Cells      []string   `json:"cells"`
+		ScrapedAt  time.Time  `json:"scraped_at"`
+		CountryId  *uuid.UUID `json:"country_id"`
+		ResolvedBy *uuid.UUID `json:"resolved_by"`
+		ResolvedAt *time.Time `json:"resolved_at"`
+		CreatedBy  uuid.UUID  `json:"created_by"`
+		CreatedAt  time.Time  `json:"created_at"`
+		UpdatedBy  uuid.UUID  `json:"updated_by"`
+		UpdatedAt  time.Time  `json:"updated_at"`
+	}
+)
+
+func (s UnmatchedScrapeRowModel) Response() UnmatchedScrapeRowResponse {
+
+	response := UnmatchedScrapeRowResponse{
+		Id:        s.Id,
+		Source:    s.Source,
+		Name:      s.Name,
+		Cells:     s.Cells,
+		ScrapedAt: s.ScrapedAt,
+		CreatedBy: s.CreatedBy,
+		CreatedAt: s.CreatedAt,
+		UpdatedBy: s.UpdatedBy.UUID,
+		UpdatedAt: s.UpdatedAt.Time,
+	}
+
+	if s.CountryId.Valid {
+		response.CountryId = &s.CountryId.UUID
+	}
+	if s.ResolvedBy.Valid {
+		response.ResolvedBy = &s.ResolvedBy.UUID
+	}
+	if s.ResolvedAt.Valid {
+		response.ResolvedAt = &s.ResolvedAt.Time
+	}
+
+	return response
+
+}
+
+func GetOneUnmatchedScrapeRow(ctx context.Context, db helpers.Querier, id uuid.UUID) (UnmatchedScrapeRowModel, error) {
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			source,
+			name,
+			cells,
+			scraped_at,
+			country_id,
+			resolved_by,
+			resolved_at,
+			created_by,
+			created_at,
+			updated_by,
+			updated_at
+		FROM
+			unmatched_scrape_row
+		WHERE
+			id = $1
+	`)
+
+	var row UnmatchedScrapeRowModel
+	err := db.QueryRowContext(ctx, query, id).Scan(
+		&row.Id,
+		&row.Source,
+		&row.Name,
+		pq.Array(&row.Cells),
+		&row.ScrapedAt,
+		&row.CountryId,
+		&row.ResolvedBy,
+		&row.ResolvedAt,
+		&row.CreatedBy,
+		&row.CreatedAt,
+		&row.UpdatedBy,
+		&row.UpdatedAt,
+	)
+
+	if err != nil {
+		return UnmatchedScrapeRowModel{}, err
+	}
+
+	return row, nil
+
+}
+
+// GetAllUnmatchedScrapeRow lists the rows still waiting for review, or every row when
+// resolved is true.
+func GetAllUnmatchedScrapeRow(ctx context.Context, db helpers.Querier, resolved bool) ([]UnmatchedScrapeRowModel, error) {
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			source,
+			name,
+			cells,
+			scraped_at,
+			country_id,
+			resolved_by,
+			resolved_at,
+			created_by,
+			created_at,
+			updated_by,
+			updated_at
+		FROM
+			unmatched_scrape_row
+		WHERE
+			$1 OR resolved_at IS NULL
+		ORDER BY
+			scraped_at DESC, name
+	`)
+
+	rows, err := db.QueryContext(ctx, query, resolved)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	var unmatchedRows []UnmatchedScrapeRowModel
+	for rows.Next() {
+		var row UnmatchedScrapeRowModel
+
+		rows.Scan(
+			&row.Id,
+			&row.Source,
+			&row.Name,
+			pq.Array(&row.Cells),
+			&row.ScrapedAt,
+			&row.CountryId,
+			&row.ResolvedBy,
+			&row.ResolvedAt,
+			&row.CreatedBy,
+			&row.CreatedAt,
+			&row.UpdatedBy,
+			&row.UpdatedAt,
+		)
+
+		unmatchedRows = append(unmatchedRows, row)
+	}
+
+	return unmatchedRows, nil
+
+}
+
+// Upsert queues the row, or refreshes the cells and scrape time of the pending
+// row with the same source and name.
+func (s *UnmatchedScrapeRowModel) Upsert(ctx context.Context, db helpers.Querier) error {
+
+	query := fmt.Sprintf(`
+		INSERT INTO unmatched_scrape_row(
+			source,
+			name,
+			cells,
+			scraped_at,
+			created_by,
+			created_at
+		)VALUES(
+			$1,$2,$3,$4,$5,now())
+		ON CONFLICT (source, LOWER(name)) WHERE resolved_at IS NULL
+		DO UPDATE SET
+			cells=EXCLUDED.cells,
+			scraped_at=EXCLUDED.scraped_at,
+			updated_at=NOW(),
+			updated_by=EXCLUDED.created_by
+		RETURNING
+			id, created_at, created_by
+	`)
+
+	err := db.QueryRowContext(ctx, query,
+		s.Source, s.Name, pq.Array(s.Cells), s.ScrapedAt, s.CreatedBy).Scan(
+		&s.Id, &s.CreatedAt, &s.CreatedBy,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+
+}
+
+func (s *UnmatchedScrapeRowModel) Resolve(ctx context.Context, db helpers.Querier) error {
+
+	query := fmt.Sprintf(`
+		UPDATE unmatched_scrape_row
+		SET
+			country_id=$1,
+			resolved_by=$2,
+			resolved_at=NOW(),
+			updated_at=NOW(),
+			updated_by=$2
+		WHERE
+			id=$3
+		RETURNING
+			resolved_at,updated_at
+	`)
+
+	err := db.QueryRowContext(ctx, query, s.CountryId, s.ResolvedBy, s.Id).Scan(
+		&s.ResolvedAt, &s.UpdatedAt,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	s.UpdatedBy = s.ResolvedBy
+
+	return nil
+
+}
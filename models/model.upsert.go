@@ -0,0 +1,76 @@
+package models
+
+import (
+	"corona/helpers"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+)
+
+type (
+	// UpsertResult tells what an upsert did to the row it was given.
+	UpsertResult int
+
+	// UpsertSummary counts the outcome of a batch of upserts.
+	UpsertSummary struct {
+		Inserted  int `json:"inserted"`
+		Updated   int `json:"updated"`
+		Unchanged int `json:"unchanged"`
+	}
+)
+
+// uniqueViolation is the code of the Postgres errors raised by writes that would
+// break a unique index.
+const uniqueViolation = "23505"
+
+const (
+	UpsertUnchanged UpsertResult = iota
+	UpsertInserted
+	UpsertUpdated
+)
+
+func (r UpsertResult) String() string {
+
+	switch r {
+	case UpsertInserted:
+		return "inserted"
+	case UpsertUpdated:
+		return "updated"
+	default:
+		return "unchanged"
+	}
+
+}
+
+func (s *UpsertSummary) Add(result UpsertResult) {
+
+	switch result {
+	case UpsertInserted:
+		s.Inserted++
+	case UpsertUpdated:
+		s.Updated++
+	default:
+		s.Unchanged++
+	}
+
+}
+
+// upsertResult reads the inserted flag returned as (xmax = 0) by an upsert.
+func upsertResult(inserted bool) UpsertResult {
+
+	if inserted {
+		return UpsertInserted
+	}
+
+	return UpsertUpdated
+}
+
+// duplicateError returns err as a helpers.ErrDuplicate, described by format and
+// args, when it breaks a unique index, and as is otherwise.
+func duplicateError(err error, format string, args ...interface{}) error {
+
+	if pqErr, ok := err.(*pq.Error); ok && pqErr.Code == uniqueViolation {
+		return errors.Wrapf(helpers.ErrDuplicate, format, args...)
+	}
+
+	return err
+}
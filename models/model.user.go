@@ -3,7 +3,6 @@ package models
 import (
 	"context"
 	"corona/helpers"
-	"database/sql"
 	"fmt"
 	"github.com/lib/pq"
 	uuid "github.com/satori/go.uuid"
@@ -35,9 +34,46 @@ type (
 		UpdatedBy    uuid.UUID            `json:"updated_by"`
 		UpdatedAt    time.Time            `json:"updated_at"`
 	}
+
+	// UserDetailModel is a user read along with its subscription, so lists can
+	// build their responses without a query per row.
+	UserDetailModel struct {
+		User         UserModel
+		Subscription SubscriptionModel
+	}
 )
 
-func (u UserModel) Response(ctx context.Context, db *sql.DB, logger *helpers.Logger) (UserResponse, error) {
+// userDetailColumns selects a UserDetailModel from "user" u joined with
+// subscription s, in the order of UserDetailModel.fields.
+const userDetailColumns = `
+			u.id,
+			u.subscription_id,
+			u.name,
+			u.email,
+			u.password,
+			u.is_active,
+			u.created_by,
+			u.created_at,
+			u.updated_by,
+			u.updated_at,
+			s.id,
+			s.subscription_type,
+			s.request_per_day,
+			s.is_delete,
+			s.created_by,
+			s.created_at,
+			s.updated_by,
+			s.updated_at`
+
+// userDetailFrom joins "user" u with subscription s.
+const userDetailFrom = `
+			"user" u
+		INNER JOIN
+			subscription s
+		ON
+			u.subscription_id = s.id`
+
+func (u UserModel) Response(ctx context.Context, db helpers.Querier, logger *helpers.Logger) (UserResponse, error) {
 
 	subscription, err := GetOneSubscription(ctx, db, u.SubscriptionId)
 
@@ -46,21 +82,52 @@ func (u UserModel) Response(ctx context.Context, db *sql.DB, logger *helpers.Log
 		return UserResponse{}, err
 	}
 
+	return UserDetailModel{User: u, Subscription: subscription}.Response(), nil
+
+}
+
+func (u UserDetailModel) Response() UserResponse {
+
 	return UserResponse{
-		Id:           u.Id,
-		Subscription: subscription.Response(),
-		Name:         u.Name,
-		Email:        u.Email,
-		IsActive:     u.IsActive,
-		CreatedBy:    u.CreatedBy,
-		CreatedAt:    u.CreatedAt,
-		UpdatedBy:    u.UpdatedBy.UUID,
-		UpdatedAt:    u.UpdatedAt.Time,
-	}, nil
+		Id:           u.User.Id,
+		Subscription: u.Subscription.Response(),
+		Name:         u.User.Name,
+		Email:        u.User.Email,
+		IsActive:     u.User.IsActive,
+		CreatedBy:    u.User.CreatedBy,
+		CreatedAt:    u.User.CreatedAt,
+		UpdatedBy:    u.User.UpdatedBy.UUID,
+		UpdatedAt:    u.User.UpdatedAt.Time,
+	}
+
+}
+
+func (u *UserDetailModel) fields() []interface{} {
+
+	return []interface{}{
+		&u.User.Id,
+		&u.User.SubscriptionId,
+		&u.User.Name,
+		&u.User.Email,
+		&u.User.Password,
+		&u.User.IsActive,
+		&u.User.CreatedBy,
+		&u.User.CreatedAt,
+		&u.User.UpdatedBy,
+		&u.User.UpdatedAt,
+		&u.Subscription.Id,
+		&u.Subscription.SubscriptionType,
+		&u.Subscription.RequestPerDay,
+		&u.Subscription.IsDelete,
+		&u.Subscription.CreatedBy,
+		&u.Subscription.CreatedAt,
+		&u.Subscription.UpdatedBy,
+		&u.Subscription.UpdatedAt,
+	}
 
 }
 
-func GetOneUser(ctx context.Context, db *sql.DB, id uuid.UUID) (UserModel, error) {
+func GetOneUser(ctx context.Context, db helpers.Querier, id uuid.UUID) (UserModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -102,7 +169,7 @@ func GetOneUser(ctx context.Context, db *sql.DB, id uuid.UUID) (UserModel, error
 
 }
 
-func GetOneUserByEmail(ctx context.Context, db *sql.DB, email string) (UserModel, error) {
+func GetOneUserByEmail(ctx context.Context, db helpers.Querier, email string) (UserModel, error) {
 
 	query := fmt.Sprintf(`
 		SELECT
@@ -144,33 +211,36 @@ func GetOneUserByEmail(ctx context.Context, db *sql.DB, email string) (UserModel
 
 }
 
-func GetAllUser(ctx context.Context, db *sql.DB, filter helpers.Filter) ([]UserModel, error) {
+// userSortColumns are the sorts of GetAllUser.
+var userSortColumns = map[string]string{
+	SortName: "u.name",
+}
 
-	var searchQuery string
+func userBuilder(filter helpers.Filter) *helpers.QueryBuilder {
 
-	if filter.Search != "" {
-		searchQuery = fmt.Sprintf(`WHERE LOWER(name) LIKE LOWER('%%%s%%')`, filter.Search)
+	builder := helpers.NewQueryBuilder(filter)
+	builder.Search("u.name")
+	builder.Range(noRangeColumns)
+	builder.OrderBy(userSortColumns, SortName, "u.id")
+
+	return builder
+}
+
+func GetAllUser(ctx context.Context, db helpers.Querier, filter helpers.Filter) ([]UserDetailModel, error) {
+
+	clauses, args, err := userBuilder(filter).Build()
+
+	if err != nil {
+		return nil, err
 	}
 
 	query := fmt.Sprintf(`
-		SELECT
-			id,
-			subscription_id,
-			name,
-			email,
-			is_active,
-			created_by,
-			created_at,
-			updated_by,
-			updated_at
-		FROM user
-		%s 
-		ORDER BY
-			name  %s
-		LIMIT $1 OFFSET $2`,
-		searchQuery, filter.Dir)
+		SELECT %s
+		FROM %s
+		%s`,
+		userDetailColumns, userDetailFrom, clauses)
 
-	rows, err := db.QueryContext(ctx, query, filter.Limit, filter.Offset)
+	rows, err := db.QueryContext(ctx, query, args...)
 
 	if err != nil {
 		return nil, err
@@ -178,31 +248,34 @@ func GetAllUser(ctx context.Context, db *sql.DB, filter helpers.Filter) ([]UserM
 
 	defer rows.Close()
 
-	var users []UserModel
+	var users []UserDetailModel
 	for rows.Next() {
-		var user UserModel
-
-		rows.Scan(
-			&user.Id,
-			&user.SubscriptionId,
-			&user.Name,
-			&user.Email,
-			&user.Password,
-			&user.IsActive,
-			&user.CreatedBy,
-			&user.CreatedAt,
-			&user.UpdatedBy,
-			&user.UpdatedAt,
-		)
+		var user UserDetailModel
+
+		err := rows.Scan(user.fields()...)
+
+		if err != nil {
+			return nil, err
+		}
 
 		users = append(users, user)
 	}
 
-	return users, nil
+	return users, rows.Err()
+
+}
+
+func CountUser(ctx context.Context, db helpers.Querier, filter helpers.Filter) (int, error) {
+	return countRows(ctx, db, userDetailFrom, userBuilder(filter))
+}
 
+// Cursor returns the cursor of the page of a list sorted as filter asks that
+// ends with s.
+func (s UserDetailModel) Cursor(filter helpers.Filter) string {
+	return helpers.NewCursor(filter, &s.User.Name, s.User.Id.String())
 }
 
-func (u *UserModel) Insert(ctx context.Context, db *sql.DB) error {
+func (u *UserModel) Insert(ctx context.Context, db helpers.Querier) error {
 
 	query := fmt.Sprintf(`
 		INSERT INTO "user"(
@@ -0,0 +1,150 @@
+package models
+
+import (
+	"context"
+	"corona/helpers"
+	"database/sql"
+	"fmt"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+type (
+	// WorldSummaryModel is the global totals of one day. IsEstimated is set when the
+	// source published no counters and the totals were summed from the country rows.
+	WorldSummaryModel struct {
+		Id             uuid.UUID
+		ReportDate     time.Time
+		TotalCases     sql.NullInt64
+		TotalDeaths    sql.NullInt64
+		TotalRecovered sql.NullInt64
+		Source         string
+		IsEstimated    bool
+		CreatedBy      uuid.UUID
+		CreatedAt      time.Time
+		UpdatedBy      uuid.NullUUID
+		UpdatedAt      pq.NullTime
+	}
+
+	WorldSummaryResponse struct {
+		ReportDate     string    `json:"report_date"`
+		TotalCases     *int64    `json:"total_cases"`
+		TotalDeaths    *int64    `json:"total_deaths"`
+		TotalRecovered *int64    `json:"total_recovered"`
+		ActiveCases    *int64    `json:"active_cases"`
+		Source         string    `json:"source"`
+		IsEstimated    bool      `json:"is_estimated"`
+		UpdatedAt      time.Time `json:"updated_at"`
+	}
+)
+
+func (s WorldSummaryModel) Response() WorldSummaryResponse {
+
+	response := WorldSummaryResponse{
+		ReportDate:     s.ReportDate.Format(helpers.DateLayout),
+		TotalCases:     nullInt64(s.TotalCases),
+		TotalDeaths:    nullInt64(s.TotalDeaths),
+		TotalRecovered: nullInt64(s.TotalRecovered),
+		Source:         s.Source,
+		IsEstimated:    s.IsEstimated,
+		UpdatedAt:      s.CreatedAt,
+	}
+
+	if s.UpdatedAt.Valid {
+		response.UpdatedAt = s.UpdatedAt.Time
+	}
+
+	if s.TotalCases.Valid && s.TotalDeaths.Valid && s.TotalRecovered.Valid {
+		active := s.TotalCases.Int64 - s.TotalDeaths.Int64 - s.TotalRecovered.Int64
+		response.ActiveCases = &active
+	}
+
+	return response
+
+}
+
+func GetLatestWorldSummary(ctx context.Context, db helpers.Querier) (WorldSummaryModel, error) {
+
+	query := fmt.Sprintf(`
+		SELECT
+			id,
+			report_date,
+			total_cases,
+			total_deaths,
+			total_recovered,
+			source,
+			is_estimated,
+			created_by,
+			created_at,
+			updated_by,
+			updated_at
+		FROM
+			world_summary
+		ORDER BY
+			report_date DESC
+		LIMIT 1
+	`)
+
+	var summary WorldSummaryModel
+	err := db.QueryRowContext(ctx, query).Scan(
+		&summary.Id,
+		&summary.ReportDate,
+		&summary.TotalCases,
+		&summary.TotalDeaths,
+		&summary.TotalRecovered,
+		&summary.Source,
+		&summary.IsEstimated,
+		&summary.CreatedBy,
+		&summary.CreatedAt,
+		&summary.UpdatedBy,
+		&summary.UpdatedAt,
+	)
+
+	if err != nil {
+		return WorldSummaryModel{}, err
+	}
+
+	return summary, nil
+
+}
+
+func (s *WorldSummaryModel) Upsert(ctx context.Context, db helpers.Querier) error {
+
+	query := fmt.Sprintf(`
+		INSERT INTO world_summary(
+			report_date,
+			total_cases,
+			total_deaths,
+			total_recovered,
+			source,
+			is_estimated,
+			created_by,
+			created_at
+		)VALUES(
+			$1,$2,$3,$4,$5,$6,$7,now())
+		ON CONFLICT (report_date)
+		DO UPDATE SET
+			total_cases=EXCLUDED.total_cases,
+			total_deaths=EXCLUDED.total_deaths,
+			total_recovered=EXCLUDED.total_recovered,
+			source=EXCLUDED.source,
+			is_estimated=EXCLUDED.is_estimated,
+			updated_at=NOW(),
+			updated_by=EXCLUDED.created_by
+		RETURNING
+			id, created_at, created_by, updated_at
+	`)
+
+	err := db.QueryRowContext(ctx, query,
+		s.ReportDate, s.TotalCases, s.TotalDeaths, s.TotalRecovered, s.Source, s.IsEstimated, s.CreatedBy).Scan(
+		&s.Id, &s.CreatedAt, &s.CreatedBy, &s.UpdatedAt,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+
+}
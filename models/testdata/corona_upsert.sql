@@ -0,0 +1 @@
+INSERT INTO corona_data( country_id, total_cases, new_cases, total_deaths, new_deaths, total_recovered, active_cases, serious_cases, total_tests, population, created_by, created_at )VALUES( $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,now()) ON CONFLICT (country_id) DO UPDATE SET total_cases=EXCLUDED.total_cases, new_cases=EXCLUDED.new_cases, total_deaths=EXCLUDED.total_deaths, new_deaths=EXCLUDED.new_deaths, total_recovered=EXCLUDED.total_recovered, active_cases=EXCLUDED.active_cases, serious_cases=EXCLUDED.serious_cases, total_tests=EXCLUDED.total_tests, population=EXCLUDED.population, updated_at=NOW(), updated_by=EXCLUDED.created_by WHERE (corona_data.total_cases, corona_data.new_cases, corona_data.total_deaths, corona_data.new_deaths, corona_data.total_recovered, corona_data.active_cases, corona_data.serious_cases, corona_data.total_tests, corona_data.population) IS DISTINCT FROM (EXCLUDED.total_cases, EXCLUDED.new_cases, EXCLUDED.total_deaths, EXCLUDED.new_deaths, EXCLUDED.total_recovered, EXCLUDED.active_cases, EXCLUDED.serious_cases, EXCLUDED.total_tests, EXCLUDED.population) RETURNING id, created_at, created_by, updated_at, (xmax = 0)
@@ -0,0 +1 @@
+SELECT co.id, co.name, co.code, co.created_by, co.created_at, co.updated_by, co.updated_at, COUNT(cd.id), SUM(cd.total_cases)::BIGINT, SUM(cd.total_deaths)::BIGINT, SUM(cd.total_recovered)::BIGINT, SUM(cd.active_cases)::BIGINT, SUM(cd.total_tests)::BIGINT, SUM(cd.population)::BIGINT FROM continent co LEFT JOIN country c ON c.continent_id = co.id LEFT JOIN corona_data cd ON cd.country_id = c.id WHERE co.id = $1 GROUP BY co.id ORDER BY co.name
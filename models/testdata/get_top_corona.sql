@@ -0,0 +1 @@
+SELECT cd.id, cd.country_id, cd.total_cases, cd.new_cases, cd.total_deaths, cd.new_deaths, cd.total_recovered, cd.active_cases, cd.serious_cases, cd.total_tests, cd.population, cd.created_by, cd.created_at, cd.updated_by, cd.updated_at, c.id, c.continent_id, c.name, c.code, c.alpha3, c.numeric_code, c.created_by, c.created_at, c.updated_by, c.updated_at, co.id, co.name, co.code, co.created_by, co.created_at, co.updated_by, co.updated_at FROM corona_data cd INNER JOIN country c ON cd.country_id = c.id INNER JOIN continent co ON c.continent_id = co.id WHERE ROUND((cd.total_deaths::float8 / NULLIF(cd.population, 0) * 1e6)::numeric, 2) IS NOT NULL AND c.continent_id = $1 ORDER BY ROUND((cd.total_deaths::float8 / NULLIF(cd.population, 0) * 1e6)::numeric, 2) DESC NULLS LAST, c.id ASC LIMIT $2 OFFSET $3
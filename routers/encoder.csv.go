@@ -0,0 +1,220 @@
+package routers
+
+import (
+	"corona/helpers"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvEncoder writes the rows of the data as CSV with a header row, one column
+// per selected field. The paging meta goes into the X-Total-Count and Link
+// headers.
+type csvEncoder struct{}
+
+func (csvEncoder) Format() string {
+	return "csv"
+}
+
+func (csvEncoder) ContentType() string {
+	return "text/csv"
+}
+
+func (e csvEncoder) Encode(w http.ResponseWriter, status int, resp helpers.Response) error {
+
+	if meta := resp.Meta; meta != nil {
+
+		w.Header().Set("X-Total-Count", strconv.Itoa(meta.Total))
+
+		var links []string
+		if meta.Next != "" {
+			links = append(links, fmt.Sprintf(`<%s>; rel="next"`, meta.Next))
+		}
+		if meta.Prev != "" {
+			links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, meta.Prev))
+		}
+		if len(links) > 0 {
+			w.Header().Set("Link", strings.Join(links, ", "))
+		}
+	}
+
+	w.WriteHeader(status)
+
+	data := resp.Data
+	if table, ok := data.(helpers.Table); ok {
+		data = table.Rows()
+	}
+
+	writer := csv.NewWriter(w)
+
+	value := reflect.ValueOf(data)
+	if !value.IsValid() {
+		writer.Flush()
+		return writer.Error()
+	}
+
+	rowType := value.Type()
+	if value.Kind() == reflect.Slice {
+		rowType = rowType.Elem()
+	}
+
+	var header []string
+	walk(rowType, reflect.Value{}, "", resp.Fields, func(path string, _ reflect.Value) {
+		header = append(header, path)
+	})
+
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	writeRow := func(row reflect.Value) error {
+		var record []string
+		walk(rowType, row, "", resp.Fields, func(_ string, v reflect.Value) {
+			record = append(record, cell(v))
+		})
+		return writer.Write(record)
+	}
+
+	if value.Kind() == reflect.Slice {
+		for i := 0; i < value.Len(); i++ {
+			if err := writeRow(value.Index(i)); err != nil {
+				return err
+			}
+		}
+	} else if err := writeRow(value); err != nil {
+		return err
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// isObject reports whether values of t are flattened into several columns.
+func isObject(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !t.Implements(stringerType)
+}
+
+// columns returns the exported fields of t with their JSON names, skipping
+// those hidden from JSON.
+func columns(t reflect.Type) ([]int, []string) {
+
+	var (
+		indexes []int
+		names   []string
+	)
+	for i := 0; i < t.NumField(); i++ {
+
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+
+		if field.PkgPath != "" || name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		indexes = append(indexes, i)
+		names = append(names, name)
+	}
+
+	return indexes, names
+}
+
+// walk calls emit with the path and value of every column of a row of type t,
+// nested objects being flattened into dotted paths, e.g. country.continent.name.
+// Only the columns fields selects are emitted. v is the zero Value when there is
+// no row, or when it is nil.
+func walk(t reflect.Type, v reflect.Value, path string, fields helpers.Fields, emit func(string, reflect.Value)) {
+
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for v.IsValid() && (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			v = reflect.Value{}
+			break
+		}
+		v = v.Elem()
+	}
+
+	if path != "" && !fields.Selects(path) {
+		return
+	}
+
+	if !isObject(t) {
+		emit(path, v)
+		return
+	}
+
+	indexes, names := columns(t)
+
+	for i, index := range indexes {
+
+		if names[i] != "id" || path == "" || !fields.Collapses(path) {
+			continue
+		}
+
+		if v.IsValid() {
+			v = v.Field(index)
+		}
+
+		walk(t.Field(index).Type, v, path, helpers.Fields{}, emit)
+		return
+	}
+
+	for i, index := range indexes {
+
+		child := reflect.Value{}
+		if v.IsValid() {
+			child = v.Field(index)
+		}
+
+		childPath := names[i]
+		if path != "" {
+			childPath = path + "." + names[i]
+		}
+
+		walk(t.Field(index).Type, child, childPath, fields, emit)
+	}
+}
+
+// cell formats a value the way JSON shows it, empty when it is null.
+func cell(v reflect.Value) string {
+
+	if !v.IsValid() {
+		return ""
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.Format(time.RFC3339)
+	case fmt.Stringer:
+		return value.String()
+	case string:
+		return value
+	}
+
+	switch v.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Slice, reflect.Map, reflect.Array:
+		raw, _ := json.Marshal(v.Interface())
+		return string(raw)
+	}
+
+	return fmt.Sprint(v.Interface())
+}
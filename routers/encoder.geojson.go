@@ -0,0 +1,122 @@
+package routers
+
+import (
+	"corona/geo"
+	"corona/helpers"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+type (
+	// located is a row map formats can place, by the ISO code of its country.
+	located interface {
+		ISOCode() string
+	}
+
+	featureCollection struct {
+		Type     string        `json:"type"`
+		Features []feature     `json:"features"`
+		Meta     *helpers.Meta `json:"meta,omitempty"`
+	}
+
+	feature struct {
+		Type       string                 `json:"type"`
+		Id         interface{}            `json:"id,omitempty"`
+		Geometry   *point                 `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	}
+
+	point struct {
+		Type        string     `json:"type"`
+		Coordinates [2]float64 `json:"coordinates"`
+	}
+)
+
+// geoJSONEncoder writes rows with an ISO code, such as the figures of
+// countries, as a FeatureCollection of points at the centroid of each country.
+// The selected fields of a row are its properties, flattened as for CSV.
+// Countries without a known centroid have no geometry.
+type geoJSONEncoder struct{}
+
+var locatedType = reflect.TypeOf((*located)(nil)).Elem()
+
+func (geoJSONEncoder) Format() string {
+	return "geojson"
+}
+
+func (geoJSONEncoder) ContentType() string {
+	return "application/geo+json"
+}
+
+func (e geoJSONEncoder) Encode(w http.ResponseWriter, status int, resp helpers.Response) error {
+
+	data := resp.Data
+	if table, ok := data.(helpers.Table); ok {
+		data = table.Rows()
+	}
+
+	rows := reflect.ValueOf(data)
+	if rows.IsValid() && rows.Kind() != reflect.Slice {
+		rows = reflect.Append(reflect.MakeSlice(reflect.SliceOf(rows.Type()), 0, 1), rows)
+	}
+
+	// Whether rows can be placed depends on their type, not on whether there are
+	// any, so an empty list is refused as a full one would be.
+	if !rows.IsValid() || !rows.Type().Elem().Implements(locatedType) {
+		helpers.ErrorResponse(w, "Unsupported format", http.StatusNotAcceptable)
+		return nil
+	}
+
+	collection := featureCollection{Type: "FeatureCollection", Features: []feature{}, Meta: resp.Meta}
+
+	for i := 0; i < rows.Len(); i++ {
+
+		row := rows.Index(i).Interface().(located)
+
+		value := reflect.ValueOf(row)
+
+		f := feature{Type: "Feature", Properties: make(map[string]interface{})}
+		walk(value.Type(), value, "", resp.Fields, func(path string, v reflect.Value) {
+			f.Properties[path] = property(v)
+		})
+		walk(value.Type(), value, "", helpers.Fields{Paths: []string{"id"}}, func(_ string, v reflect.Value) {
+			f.Id = property(v)
+		})
+
+		centroid, ok, err := geo.Centroid(row.ISOCode())
+
+		if err != nil {
+			return err
+		}
+
+		if ok {
+			f.Geometry = &point{Type: "Point", Coordinates: [2]float64{centroid.Longitude, centroid.Latitude}}
+		}
+
+		collection.Features = append(collection.Features, f)
+	}
+
+	w.WriteHeader(status)
+
+	return json.NewEncoder(w).Encode(&collection)
+}
+
+// property returns v the way JSON shows it, nil when it is null.
+func property(v reflect.Value) interface{} {
+
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch value := v.Interface().(type) {
+	case time.Time:
+		return value.Format(time.RFC3339)
+	case fmt.Stringer:
+		return value.String()
+	default:
+		return value
+	}
+}
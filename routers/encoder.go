@@ -0,0 +1,117 @@
+package routers
+
+import (
+	"context"
+	"corona/helpers"
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// Encoder writes a response in one format. The format is picked by the format
+// query value, or else by the Accept header, JSON by default.
+type Encoder interface {
+	// Format is the name of the format in ?format=.
+	Format() string
+	// ContentType is the media type matched against the Accept header.
+	ContentType() string
+	// Encode writes the headers it needs, then status and resp.
+	Encode(w http.ResponseWriter, status int, resp helpers.Response) error
+}
+
+// encoderKey is the context key of the encoder picked by withFormats.
+type encoderKey struct{}
+
+// The formats a route can be written in, the first one being the default. Routes
+// answer in JSON only unless they are wrapped by withFormats.
+var (
+	jsonFormats = []Encoder{jsonEncoder{}}
+	// listFormats are for routes returning a list of rows.
+	listFormats = []Encoder{jsonEncoder{}, csvEncoder{}}
+	// locatedFormats are for lists whose rows can be placed on a map.
+	locatedFormats = []Encoder{jsonEncoder{}, csvEncoder{}, geoJSONEncoder{}}
+)
+
+// withFormats picks the encoder of the request among formats and passes it on
+// to the HandlerFunc through the context. It wraps the middlewares, so a format
+// the route does not offer is refused before the request is counted against the
+// rate limit or fn runs.
+func withFormats(formats []Encoder, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encoder, ok := negotiate(r, formats)
+		if !ok {
+			helpers.ErrorResponse(w, "Unsupported format", http.StatusNotAcceptable)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), encoderKey{}, encoder)))
+	})
+}
+
+// negotiate returns the encoder among formats the request asks for. It fails
+// only for a format value the route does not offer: an Accept header without a
+// type it offers gets the default.
+func negotiate(r *http.Request, formats []Encoder) (Encoder, bool) {
+
+	if format := r.URL.Query().Get("format"); format != "" {
+
+		for _, encoder := range formats {
+			if strings.EqualFold(encoder.Format(), format) {
+				return encoder, true
+			}
+		}
+
+		return nil, false
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+
+		if err != nil || params["q"] == "0" {
+			continue
+		}
+
+		for _, encoder := range formats {
+			if mediaType == encoder.ContentType() {
+				return encoder, true
+			}
+		}
+	}
+
+	return formats[0], true
+}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) Format() string {
+	return "json"
+}
+
+func (jsonEncoder) ContentType() string {
+	return "application/json"
+}
+
+func (e jsonEncoder) Encode(w http.ResponseWriter, status int, resp helpers.Response) error {
+
+	data, err := resp.Fields.Project(resp.Data)
+
+	if err != nil {
+		return err
+	}
+
+	resp.Data = data
+
+	// Encoded before the status is written, so a failure can still be an error.
+	body, err := json.Marshal(&resp)
+
+	if err != nil {
+		return err
+	}
+
+	w.WriteHeader(status)
+
+	_, err = w.Write(append(body, '\n'))
+
+	return err
+}
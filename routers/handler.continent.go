@@ -8,7 +8,7 @@ import (
 	"net/http"
 )
 
-func HandlerContinentList(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerContinentList(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
@@ -18,10 +18,10 @@ func HandlerContinentList(w http.ResponseWriter, r *http.Request) (interface{},
 		return nil, helpers.ErrorWrap(err, "handler", "HandlerContinentList/parseFilter",
 			helpers.BadRequestMessage, http.StatusBadRequest)
 	}
-	return continentService.List(ctx, filter)
+	return h.services.Continent.List(ctx, filter)
 }
 
-func HandlerContinentDetail(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerContinentDetail(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
@@ -35,5 +35,5 @@ func HandlerContinentDetail(w http.ResponseWriter, r *http.Request) (interface{}
 
 	param := api.ContinentDetailParam{Id: dataId}
 
-	return continentService.Detail(ctx, param)
+	return h.services.Continent.Detail(ctx, param)
 }
@@ -5,9 +5,11 @@ import (
 	"corona/helpers"
 	"github.com/gorilla/mux"
 	"net/http"
+	"strconv"
+	"time"
 )
 
-func HandlerCoronaList(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerCoronaList(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
@@ -17,17 +19,31 @@ func HandlerCoronaList(w http.ResponseWriter, r *http.Request) (interface{}, *he
 		return nil, helpers.ErrorWrap(err, "handler", "HandlerCoronaList/parseFilter",
 			helpers.BadRequestMessage, http.StatusBadRequest)
 	}
-	return coronaService.List(ctx, filter)
+	return h.services.Corona.List(ctx, filter)
 }
 
-func HandlerCoronaAdd(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerCoronaAdd(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
-	return coronaService.Add(ctx)
+	return h.services.Corona.Add(ctx)
 }
 
-func HandlerCoronaByCountry(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerCoronaWorld(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+
+	ctx := r.Context()
+
+	return h.services.Corona.World(ctx)
+}
+
+func (h *Handlers) HandlerCoronaContinents(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+
+	ctx := r.Context()
+
+	return h.services.Corona.Continents(ctx)
+}
+
+func (h *Handlers) HandlerCoronaByCountry(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
@@ -37,10 +53,10 @@ func HandlerCoronaByCountry(w http.ResponseWriter, r *http.Request) (interface{}
 
 	param := api.ByCountryParam{Country: country}
 
-	return coronaService.ByCountry(ctx, param)
+	return h.services.Corona.ByCountry(ctx, param)
 }
 
-func HandlerCoronaByContinent(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerCoronaByContinent(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
@@ -57,5 +73,119 @@ func HandlerCoronaByContinent(w http.ResponseWriter, r *http.Request) (interface
 
 	param := api.ByContinentParam{Continent: continent}
 
-	return coronaService.ByContinent(ctx, filter, param)
+	return h.services.Corona.ByContinent(ctx, filter, param)
+}
+
+func (h *Handlers) HandlerCoronaTop(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+
+	ctx := r.Context()
+
+	query := r.URL.Query()
+
+	param := api.TopParam{
+		Metric:    query.Get("metric"),
+		Continent: query.Get("continent"),
+	}
+
+	if value := query.Get("n"); value != "" {
+
+		limit, err := strconv.Atoi(value)
+
+		if err != nil {
+			return nil, helpers.ErrorWrap(err, "handler", "HandlerCoronaTop/parseLimit",
+				helpers.BadRequestMessage, http.StatusBadRequest)
+		}
+
+		param.Limit = limit
+	}
+
+	return h.services.Corona.Top(ctx, param)
+}
+
+func (h *Handlers) HandlerCoronaCompare(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+
+	ctx := r.Context()
+
+	query := r.URL.Query()
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, "handler", "HandlerCoronaCompare/parseDateRange",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	param := api.CompareParam{
+		Countries: splitList(query.Get("countries")),
+		Metrics:   splitList(query.Get("metrics")),
+		From:      from,
+		To:        to,
+	}
+
+	return h.services.Corona.Compare(ctx, param)
+}
+
+func (h *Handlers) HandlerCoronaTimeline(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+
+	ctx := r.Context()
+
+	params := mux.Vars(r)
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, "handler", "HandlerCoronaTimeline/parseDateRange",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	param := api.TimelineParam{
+		Country: params["country"],
+		From:    from,
+		To:      to,
+		Metrics: splitList(r.URL.Query().Get("metrics")),
+	}
+
+	return h.services.Corona.Timeline(ctx, param)
+}
+
+func (h *Handlers) HandlerCoronaTrends(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+
+	ctx := r.Context()
+
+	params := mux.Vars(r)
+
+	from, to, err := parseDateRange(r)
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, "handler", "HandlerCoronaTrends/parseDateRange",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	param := api.TrendsParam{
+		Country: params["country"],
+		From:    from,
+		To:      to,
+	}
+
+	return h.services.Corona.Trends(ctx, param)
+}
+
+// parseDateRange reads the optional from and to query values, left zero when
+// absent.
+func parseDateRange(r *http.Request) (from time.Time, to time.Time, err error) {
+
+	query := r.URL.Query()
+
+	if value := query.Get("from"); value != "" {
+		from, err = time.Parse(helpers.DateLayout, value)
+		if err != nil {
+			return
+		}
+	}
+
+	if value := query.Get("to"); value != "" {
+		to, err = time.Parse(helpers.DateLayout, value)
+		if err != nil {
+			return
+		}
+	}
+
+	return
 }
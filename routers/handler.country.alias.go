@@ -0,0 +1,74 @@
+package routers
+
+import (
+	"corona/api"
+	"corona/helpers"
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+	"net/http"
+)
+
+func (h *Handlers) HandlerCountryAliasList(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+
+	ctx := r.Context()
+
+	params := mux.Vars(r)
+
+	countryId, err := uuid.FromString(params["id"])
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, "handler", "HandlerCountryAliasList/parseID",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	param := api.CountryAliasListParam{CountryId: countryId}
+
+	return h.services.CountryAlias.List(ctx, param)
+}
+
+func (h *Handlers) HandlerCountryAliasAdd(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+
+	ctx := r.Context()
+
+	params := mux.Vars(r)
+
+	countryId, err := uuid.FromString(params["id"])
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, "handler", "HandlerCountryAliasAdd/parseID",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	var param api.CountryAliasAddParam
+
+	err = helpers.ParseBodyRequestData(ctx, r, &param)
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, "handler", "HandlerCountryAliasAdd/ParseBodyRequestData",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	param.CountryId = countryId
+
+	return h.services.CountryAlias.Add(ctx, param)
+}
+
+func (h *Handlers) HandlerCountryAliasDelete(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+
+	ctx := r.Context()
+
+	params := mux.Vars(r)
+
+	countryId, err := uuid.FromString(params["id"])
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, "handler", "HandlerCountryAliasDelete/parseID",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	aliasId, err := uuid.FromString(params["alias_id"])
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, "handler", "HandlerCountryAliasDelete/parseAliasID",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	param := api.CountryAliasDeleteParam{CountryId: countryId, Id: aliasId}
+
+	return h.services.CountryAlias.Delete(ctx, param)
+}
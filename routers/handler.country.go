@@ -8,7 +8,7 @@ import (
 	"net/http"
 )
 
-func HandlerCountryList(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerCountryList(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
@@ -18,10 +18,10 @@ func HandlerCountryList(w http.ResponseWriter, r *http.Request) (interface{}, *h
 		return nil, helpers.ErrorWrap(err, "handler", "HandlerCountryList/parseFilter",
 			helpers.BadRequestMessage, http.StatusBadRequest)
 	}
-	return countryService.List(ctx, filter)
+	return h.services.Country.List(ctx, filter)
 }
 
-func HandlerCountryDetail(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerCountryDetail(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
@@ -35,10 +35,10 @@ func HandlerCountryDetail(w http.ResponseWriter, r *http.Request) (interface{},
 
 	param := api.CountryDetailParam{Id: countryId}
 
-	return countryService.Detail(ctx, param)
+	return h.services.Country.Detail(ctx, param)
 }
 
-func HandlerCountryAdd(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerCountryAdd(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
@@ -52,5 +52,5 @@ func HandlerCountryAdd(w http.ResponseWriter, r *http.Request) (interface{}, *he
 
 	}
 
-	return countryService.Add(ctx, param)
+	return h.services.Country.Add(ctx, param)
 }
@@ -8,14 +8,14 @@ import (
 	"net/http"
 )
 
-func HandlerRateLimitList(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerRateLimitList(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
-	return rateLimitService.List(ctx)
+	return h.services.RateLimit.List(ctx)
 }
 
-func HandlerRateLimitDetail(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerRateLimitDetail(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
@@ -29,5 +29,5 @@ func HandlerRateLimitDetail(w http.ResponseWriter, r *http.Request) (interface{}
 
 	param := api.RateLimitDetailParam{Id: limitID}
 
-	return rateLimitService.Detail(ctx, param)
+	return h.services.RateLimit.Detail(ctx, param)
 }
@@ -8,14 +8,14 @@ import (
 	"net/http"
 )
 
-func HandlerSubscriptionList(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerSubscriptionList(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
-	return subscriptionService.List(ctx)
+	return h.services.Subscription.List(ctx)
 }
 
-func HandlerSubscriptionDetail(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerSubscriptionDetail(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
@@ -29,5 +29,5 @@ func HandlerSubscriptionDetail(w http.ResponseWriter, r *http.Request) (interfac
 
 	param := api.SubscriptionDetailParam{Id: subscriptionID}
 
-	return subscriptionService.Detail(ctx, param)
+	return h.services.Subscription.Detail(ctx, param)
 }
@@ -8,14 +8,14 @@ import (
 	"net/http"
 )
 
-func HandlerTokenList(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerTokenList(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
-	return tokenService.List(ctx)
+	return h.services.Token.List(ctx)
 }
 
-func HandlerTokenDetail(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerTokenDetail(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
@@ -29,5 +29,5 @@ func HandlerTokenDetail(w http.ResponseWriter, r *http.Request) (interface{}, *h
 
 	param := api.TokenDetailParam{Id: tokenID}
 
-	return tokenService.Detail(ctx, param)
+	return h.services.Token.Detail(ctx, param)
 }
@@ -0,0 +1,53 @@
+package routers
+
+import (
+	"corona/api"
+	"corona/helpers"
+	"github.com/gorilla/mux"
+	uuid "github.com/satori/go.uuid"
+	"net/http"
+	"strconv"
+)
+
+func (h *Handlers) HandlerUnmatchedScrapeRowList(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+
+	ctx := r.Context()
+
+	var param api.UnmatchedScrapeRowListParam
+
+	if all := r.URL.Query().Get("all"); all != "" {
+		value, err := strconv.ParseBool(all)
+		if err != nil {
+			return nil, helpers.ErrorWrap(err, "handler", "HandlerUnmatchedScrapeRowList/parseAll",
+				helpers.BadRequestMessage, http.StatusBadRequest)
+		}
+		param.All = value
+	}
+
+	return h.services.UnmatchedScrapeRow.List(ctx, param)
+}
+
+func (h *Handlers) HandlerUnmatchedScrapeRowResolve(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+
+	ctx := r.Context()
+
+	params := mux.Vars(r)
+
+	rowId, err := uuid.FromString(params["id"])
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, "handler", "HandlerUnmatchedScrapeRowResolve/parseID",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	var param api.UnmatchedScrapeRowResolveParam
+
+	err = helpers.ParseBodyRequestData(ctx, r, &param)
+	if err != nil {
+		return nil, helpers.ErrorWrap(err, "handler", "HandlerUnmatchedScrapeRowResolve/ParseBodyRequestData",
+			helpers.BadRequestMessage, http.StatusBadRequest)
+	}
+
+	param.Id = rowId
+
+	return h.services.UnmatchedScrapeRow.Resolve(ctx, param)
+}
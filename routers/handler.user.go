@@ -6,7 +6,7 @@ import (
 	"net/http"
 )
 
-func HandlerUserRegister(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerUserRegister(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
@@ -19,10 +19,10 @@ func HandlerUserRegister(w http.ResponseWriter, r *http.Request) (interface{}, *
 
 	}
 
-	return userService.Register(ctx, param)
+	return h.services.User.Register(ctx, param)
 }
 
-func HandlerUserLogin(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+func (h *Handlers) HandlerUserLogin(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
 
 	ctx := r.Context()
 
@@ -35,5 +35,5 @@ func HandlerUserLogin(w http.ResponseWriter, r *http.Request) (interface{}, *hel
 			helpers.BadRequestMessage, http.StatusBadRequest)
 	}
 
-	return userService.Login(ctx, param)
+	return h.services.User.Login(ctx, param)
 }
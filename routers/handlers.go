@@ -2,23 +2,38 @@ package routers
 
 import (
 	"corona/helpers"
-	"corona/middleware"
-	"encoding/json"
 	"github.com/gorilla/mux"
 	"net/http"
+	"strings"
 )
 
 type (
 	HandlerFunc func(http.ResponseWriter, *http.Request) (interface{}, *helpers.Error)
 )
 
+// ServeHTTP writes the result of fn in the format picked by withFormats, or in
+// JSON, with the fields the request selects. Errors are always written as JSON.
 func (fn HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	var errs []string
 	r.ParseForm()
+	encoder, ok := r.Context().Value(encoderKey{}).(Encoder)
+	if !ok {
+		if encoder, ok = negotiate(r, jsonFormats); !ok {
+			helpers.ErrorResponse(w, "Unsupported format", http.StatusNotAcceptable)
+			return
+		}
+	}
+	fields, fieldsErr := helpers.ParseFields(r)
+	if fieldsErr != nil {
+		helpers.ErrorResponse(w, helpers.BadRequestMessage, http.StatusBadRequest)
+		return
+	}
 	data, err := fn(w, r)
+	status := http.StatusOK
 	if err != nil {
 		errs = append(errs, err.Error())
-		w.WriteHeader(err.StatusCode)
+		status = err.StatusCode
+		encoder = jsonEncoder{}
 	}
 	resp := helpers.Response{
 		Data: data,
@@ -26,60 +41,129 @@ func (fn HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			Errors: errs,
 		},
 	}
-	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(&resp); err != nil {
-		return
+	if page, ok := data.(helpers.Page); ok {
+		resp.Data, resp.Meta = page.Data, page.Meta(r.URL)
+	}
+	if err == nil {
+		resp.Fields = fields
+	}
+	w.Header().Set("Content-Type", encoder.ContentType())
+	hw := &headerWriter{ResponseWriter: w}
+	if err := encoder.Encode(hw, status, resp); err != nil && !hw.wroteHeader {
+		helpers.ErrorResponse(w, helpers.InternalServerError, http.StatusInternalServerError)
 	}
 }
 
-func InitHandlers() *mux.Router {
-	r := mux.NewRouter()
+// headerWriter tells whether the status was written, after which an encoder that
+// fails can no longer answer with an error.
+type headerWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *headerWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *headerWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+// handle logs the error of fn, if any, before it is written to the client.
+func (h *Handlers) handle(fn HandlerFunc) HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+		data, err := fn(w, r)
+		if err != nil {
+			h.logger.Err.Errorf("error : %v", err.Err)
+		}
+		return data, err
+	}
+}
 
-	http.Handle("/", r)
+// splitList splits a comma separated query value, dropping empty items.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// Router builds the routes of the API.
+func (h *Handlers) Router() *mux.Router {
+	r := mux.NewRouter()
+	mw := h.middleware
 
 	apiV1 := r.PathPrefix("/api/v1").Subrouter()
 
-	apiV1.Handle("/coronavirus", middleware.TokenMiddleware(middleware.RateLimitMiddleware(
-		HandlerFunc(HandlerCoronaList)))).Methods(http.MethodGet)
-	apiV1.Handle("/coronavirus/continents/{continent}", middleware.TokenMiddleware(middleware.RateLimitMiddleware(
-		HandlerFunc(HandlerCoronaByContinent)))).Methods(http.MethodGet)
-	apiV1.Handle("/coronavirus/countries/{country}", middleware.TokenMiddleware(middleware.RateLimitMiddleware(
-		HandlerFunc(HandlerCoronaByCountry)))).Methods(http.MethodGet)
+	apiV1.Handle("/coronavirus", withFormats(locatedFormats, mw.TokenMiddleware(mw.RateLimitMiddleware(
+		h.handle(h.HandlerCoronaList))))).Methods(http.MethodGet)
+	apiV1.Handle("/coronavirus/world", withFormats(jsonFormats, mw.TokenMiddleware(mw.RateLimitMiddleware(
+		h.handle(h.HandlerCoronaWorld))))).Methods(http.MethodGet)
+	apiV1.Handle("/coronavirus/top", withFormats(jsonFormats, mw.TokenMiddleware(mw.RateLimitMiddleware(
+		h.handle(h.HandlerCoronaTop))))).Methods(http.MethodGet)
+	apiV1.Handle("/coronavirus/compare", withFormats(jsonFormats, mw.TokenMiddleware(mw.RateLimitMiddleware(
+		h.handle(h.HandlerCoronaCompare))))).Methods(http.MethodGet)
+	apiV1.Handle("/coronavirus/continents", withFormats(jsonFormats, mw.TokenMiddleware(mw.RateLimitMiddleware(
+		h.handle(h.HandlerCoronaContinents))))).Methods(http.MethodGet)
+	apiV1.Handle("/coronavirus/continents/{continent}", withFormats(listFormats, mw.TokenMiddleware(mw.RateLimitMiddleware(
+		h.handle(h.HandlerCoronaByContinent))))).Methods(http.MethodGet)
+	apiV1.Handle("/coronavirus/countries/{country}", withFormats(jsonFormats, mw.TokenMiddleware(mw.RateLimitMiddleware(
+		h.handle(h.HandlerCoronaByCountry))))).Methods(http.MethodGet)
+	apiV1.Handle("/coronavirus/countries/{country}/timeline", withFormats(jsonFormats, mw.TokenMiddleware(mw.RateLimitMiddleware(
+		h.handle(h.HandlerCoronaTimeline))))).Methods(http.MethodGet)
+	apiV1.Handle("/coronavirus/countries/{country}/trends", withFormats(jsonFormats, mw.TokenMiddleware(mw.RateLimitMiddleware(
+		h.handle(h.HandlerCoronaTrends))))).Methods(http.MethodGet)
 
 	apiV1.Handle("/coronavirus",
-		HandlerFunc(HandlerCoronaAdd)).Methods(http.MethodPost)
+		h.handle(h.HandlerCoronaAdd)).Methods(http.MethodPost)
 
+	apiV1.Handle("/countries", withFormats(locatedFormats,
+		h.handle(h.HandlerCountryList))).Methods(http.MethodGet)
 	apiV1.Handle("/countries",
-		HandlerFunc(HandlerCountryList)).Methods(http.MethodGet)
-	apiV1.Handle("/countries",
-		HandlerFunc(HandlerCountryAdd)).Methods(http.MethodPost)
+		h.handle(h.HandlerCountryAdd)).Methods(http.MethodPost)
 	apiV1.Handle("/countries/{id}",
-		HandlerFunc(HandlerCountryDetail)).Methods(http.MethodGet)
-
-	apiV1.Handle("/continents",
-		HandlerFunc(HandlerContinentList)).Methods(http.MethodGet)
+		h.handle(h.HandlerCountryDetail)).Methods(http.MethodGet)
+	apiV1.Handle("/countries/{id}/aliases",
+		h.handle(h.HandlerCountryAliasList)).Methods(http.MethodGet)
+	apiV1.Handle("/countries/{id}/aliases",
+		h.handle(h.HandlerCountryAliasAdd)).Methods(http.MethodPost)
+	apiV1.Handle("/countries/{id}/aliases/{alias_id}",
+		h.handle(h.HandlerCountryAliasDelete)).Methods(http.MethodDelete)
+
+	apiV1.Handle("/continents", withFormats(listFormats,
+		h.handle(h.HandlerContinentList))).Methods(http.MethodGet)
 	apiV1.Handle("/continents/{id}",
-		HandlerFunc(HandlerContinentDetail)).Methods(http.MethodGet)
+		h.handle(h.HandlerContinentDetail)).Methods(http.MethodGet)
 
 	apiV1.Handle("/subscriptions",
-		HandlerFunc(HandlerSubscriptionList)).Methods(http.MethodGet)
+		h.handle(h.HandlerSubscriptionList)).Methods(http.MethodGet)
 	apiV1.Handle("/subscriptions/{id}",
-		HandlerFunc(HandlerSubscriptionDetail)).Methods(http.MethodGet)
+		h.handle(h.HandlerSubscriptionDetail)).Methods(http.MethodGet)
 
 	apiV1.Handle("/rate_limits",
-		HandlerFunc(HandlerRateLimitList)).Methods(http.MethodGet)
+		h.handle(h.HandlerRateLimitList)).Methods(http.MethodGet)
 	apiV1.Handle("/rate_limits/{id}",
-		HandlerFunc(HandlerRateLimitDetail)).Methods(http.MethodGet)
+		h.handle(h.HandlerRateLimitDetail)).Methods(http.MethodGet)
 
 	apiV1.Handle("/tokens",
-		HandlerFunc(HandlerTokenList)).Methods(http.MethodGet)
+		h.handle(h.HandlerTokenList)).Methods(http.MethodGet)
 	apiV1.Handle("/tokens/{id}",
-		HandlerFunc(HandlerTokenDetail)).Methods(http.MethodGet)
+		h.handle(h.HandlerTokenDetail)).Methods(http.MethodGet)
+
+	apiV1.Handle("/unmatched_rows",
+		h.handle(h.HandlerUnmatchedScrapeRowList)).Methods(http.MethodGet)
+	apiV1.Handle("/unmatched_rows/{id}/resolve",
+		h.handle(h.HandlerUnmatchedScrapeRowResolve)).Methods(http.MethodPost)
 
 	apiV1.Handle("/register",
-		HandlerFunc(HandlerUserRegister)).Methods(http.MethodPost)
+		h.handle(h.HandlerUserRegister)).Methods(http.MethodPost)
 	apiV1.Handle("/login",
-		HandlerFunc(HandlerUserLogin)).Methods(http.MethodPost)
+		h.handle(h.HandlerUserLogin)).Methods(http.MethodPost)
 
 	return r
 
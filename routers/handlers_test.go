@@ -0,0 +1,83 @@
+package routers
+
+import (
+	"corona/api"
+	"corona/helpers"
+	"corona/middleware"
+	"corona/storage"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newTestRouter returns the routes of the API over store.
+func newTestRouter(store storage.Store) http.Handler {
+
+	logger := helpers.NewLogger()
+
+	return NewHandlers(api.NewServices(store, nil, logger), middleware.NewMiddleware(store, logger), logger).Router()
+}
+
+// TestServeHTTPEncodeError checks that data which cannot be encoded is answered
+// with an error rather than an empty success.
+func TestServeHTTPEncodeError(t *testing.T) {
+
+	fn := HandlerFunc(func(w http.ResponseWriter, r *http.Request) (interface{}, *helpers.Error) {
+		return map[string]float64{"value": math.NaN()}, nil
+	})
+
+	for _, target := range []string{"/", "/?fields=value"} {
+
+		w := httptest.NewRecorder()
+		fn.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+		var resp helpers.Response
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("%s: %v", target, err)
+		}
+
+		if w.Code != http.StatusInternalServerError || len(resp.Errors) != 1 ||
+			resp.Errors[0] != helpers.InternalServerError {
+			t.Errorf("%s got %d %+v, want an internal server error", target, w.Code, resp)
+		}
+	}
+}
+
+// TestRouterFormats checks that CSV is only offered by the list routes.
+func TestRouterFormats(t *testing.T) {
+
+	router := newTestRouter(storage.NewMemory())
+
+	tests := []struct {
+		target      string
+		accept      string
+		status      int
+		contentType string
+	}{
+		{"/api/v1/countries?format=csv", "", http.StatusOK, "text/csv"},
+		{"/api/v1/continents", "text/csv", http.StatusOK, "text/csv"},
+		{"/api/v1/countries/00000000-0000-0000-0000-000000000000?format=csv", "", http.StatusNotAcceptable, "application/json"},
+		{"/api/v1/subscriptions?format=csv", "", http.StatusNotAcceptable, "application/json"},
+		{"/api/v1/subscriptions", "text/csv", http.StatusOK, "application/json"},
+	}
+
+	for _, test := range tests {
+
+		r := httptest.NewRequest(http.MethodGet, test.target, nil)
+		if test.accept != "" {
+			r.Header.Set("Accept", test.accept)
+		}
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		if contentType := w.Header().Get("Content-Type"); w.Code != test.status ||
+			!strings.HasPrefix(contentType, test.contentType) {
+			t.Errorf("%s (Accept %q) = %d %s, want %d %s", test.target, test.accept, w.Code, contentType,
+				test.status, test.contentType)
+		}
+	}
+}
@@ -3,29 +3,22 @@ package routers
 import (
 	"corona/api"
 	"corona/helpers"
-	"database/sql"
+	"corona/middleware"
 )
 
-var (
-	dbPool              *sql.DB
-	logger              *helpers.Logger
-	coronaService       *api.CoronaModule
-	countryService      *api.CountryModule
-	continentService    *api.ContinentModule
-	userService         *api.UserModule
-	rateLimitService    *api.RateLimitModule
-	subscriptionService *api.SubscriptionModule
-	tokenService        *api.TokenModule
+type (
+	// Handlers serves the API from its services.
+	Handlers struct {
+		services   api.Services
+		middleware *middleware.Middleware
+		logger     *helpers.Logger
+	}
 )
 
-func Init(db *sql.DB, log *helpers.Logger) {
-	dbPool = db
-	logger = log
-	coronaService = api.NewCoronaModule(dbPool, logger)
-	countryService = api.NewCountryModule(dbPool, logger)
-	continentService = api.NewContinentModule(dbPool, logger)
-	userService = api.NewUserModule(dbPool, logger)
-	rateLimitService = api.NewRateLimitModule(dbPool, logger)
-	subscriptionService = api.NewSubscriptionModule(dbPool, logger)
-	tokenService = api.NewTokenModule(dbPool, logger)
+func NewHandlers(services api.Services, middleware *middleware.Middleware, logger *helpers.Logger) *Handlers {
+	return &Handlers{
+		services:   services,
+		middleware: middleware,
+		logger:     logger,
+	}
 }
@@ -2,15 +2,39 @@ package scrapper
 
 import (
 	"corona/helpers"
-	"database/sql"
+	"corona/storage"
 )
 
-var (
-	dbPool *sql.DB
-	logger *helpers.Logger
+type (
+	Option struct {
+		Source       string
+		SourceOption map[string]interface{}
+	}
+
+	// Scrapper fetches the figures from its source and matches them against the
+	// countries of its store.
+	Scrapper struct {
+		store  storage.Store
+		logger *helpers.Logger
+		source Source
+	}
 )
 
-func Init(db *sql.DB, log *helpers.Logger) {
-	dbPool = db
-	logger = log
+func NewScrapper(store storage.Store, logger *helpers.Logger, opt Option) (*Scrapper, error) {
+
+	if opt.Source == "" {
+		opt.Source = sourceWorldometers
+	}
+
+	source, err := NewSource(opt.Source, opt.SourceOption, logger)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Scrapper{
+		store:  store,
+		logger: logger,
+		source: source,
+	}, nil
 }
@@ -3,101 +3,185 @@ package scrapper
 import (
 	"context"
 	"corona/models"
-	"github.com/gocolly/colly/v2"
+	"database/sql"
 	uuid "github.com/satori/go.uuid"
-	"log"
+	"strconv"
 	"strings"
+	"time"
 )
 
-func GetCoronaData(ctx context.Context) ([]models.CoronaModel, error) {
+type (
+	// CoronaData is the outcome of one scrape: the rows matched to a country and
+	// the world totals.
+	CoronaData struct {
+		Countries []models.CoronaModel
+		World     models.WorldSummaryModel
+	}
+)
+
+func (s *Scrapper) GetCoronaData(ctx context.Context) (CoronaData, error) {
+
+	dataset, err := s.source.Fetch(ctx)
+
+	if err != nil {
+		return CoronaData{}, err
+	}
+
+	countryIds, err := s.getCountryIndex(ctx)
 
-	c := colly.NewCollector(
-		colly.AllowedDomains("www.worldometers.info"),
-	)
+	if err != nil {
+		return CoronaData{}, err
+	}
 
 	var datas []models.CoronaModel
 
-	countries, err := models.GetAllCountry(ctx, dbPool)
+	for _, record := range dataset.Records {
+
+		data := models.CoronaModel{
+			CountryId:      countryIds[strings.ToLower(record.Country)],
+			TotalCases:     parseCount(record.TotalCases),
+			NewCases:       parseCount(record.NewCases),
+			TotalDeaths:    parseCount(record.TotalDeaths),
+			NewDeaths:      parseCount(record.NewDeaths),
+			TotalRecovered: parseCount(record.TotalRecovered),
+			ActiveCases:    parseCount(record.ActiveCases),
+			SeriousCases:   parseCount(record.SeriousCases),
+			TotalTests:     parseCount(record.TotalTests),
+			Population:     parseCount(record.Population),
+		}
+
+		if data.CountryId == uuid.Nil {
+			err := s.quarantineRecord(ctx, record, dataset.FetchedAt)
+			if err != nil {
+				return CoronaData{}, err
+			}
+			continue
+		}
+
+		datas = append(datas, data)
+
+	}
+
+	return CoronaData{
+		Countries: datas,
+		World:     s.worldSummary(dataset),
+	}, nil
+
+}
+
+// worldSummary takes the world totals from the source's counters, or sums the
+// country rows when the source has none.
+func (s *Scrapper) worldSummary(dataset Dataset) models.WorldSummaryModel {
+
+	summary := models.WorldSummaryModel{
+		Source: s.source.Name(),
+	}
+
+	if dataset.World != nil {
+		summary.TotalCases = parseCount(dataset.World.TotalCases)
+		summary.TotalDeaths = parseCount(dataset.World.TotalDeaths)
+		summary.TotalRecovered = parseCount(dataset.World.TotalRecovered)
+		return summary
+	}
+
+	summary.IsEstimated = true
+
+	for _, record := range dataset.Records {
+		summary.TotalCases = addCount(summary.TotalCases, parseCount(record.TotalCases))
+		summary.TotalDeaths = addCount(summary.TotalDeaths, parseCount(record.TotalDeaths))
+		summary.TotalRecovered = addCount(summary.TotalRecovered, parseCount(record.TotalRecovered))
+	}
+
+	return summary
+}
+
+// addCount adds two figures, skipping unknown ones. The sum is only unknown when
+// both are.
+func addCount(a, b sql.NullInt64) sql.NullInt64 {
+
+	if !b.Valid {
+		return a
+	}
+
+	return sql.NullInt64{Int64: a.Int64 + b.Int64, Valid: true}
+}
+
+// getCountryIndex maps lower cased country names and the aliases of the active
+// source to country ids.
+func (s *Scrapper) getCountryIndex(ctx context.Context) (map[string]uuid.UUID, error) {
+
+	countries, err := s.store.Country.All(ctx)
 
 	if err != nil {
 		return nil, err
 	}
 
-	c.OnRequest(func(r *colly.Request) {
-		log.Println("visiting", r.URL.String())
-	})
-
-	c.OnHTML("table#main_table_countries_today tbody", func(e *colly.HTMLElement) {
-
-		e.ForEach("tr", func(_ int, el *colly.HTMLElement) {
-
-			tds := el.ChildTexts("td")
-
-			for i := 0; i < len(tds); i++ {
-				if tds[i] == "" {
-					tds[i] = "0"
-				}
-				if tds[i] == "Czechia" {
-					tds[i] = "Czech Republic"
-				}
-				if tds[i] == "DRC" {
-					tds[i] = "DR Congo"
-				}
-				if tds[i] == "Ivory Coast" {
-					tds[i] = "Cote D'Ivoire"
-				}
-				if tds[i] == "CAR" {
-					tds[i] = "Central African Republic"
-				}
-				if tds[i] == "UAE" {
-					tds[i] = "United Arab Emirates"
-				}
-				if tds[i] == "UK" {
-					tds[i] = "United Kingdom"
-				}
-				if tds[i] == "USA" {
-					tds[i] = "United States"
-				}
-				if tds[i] == "S.Korea" {
-					tds[i] = "South Korea"
-				}
-				if tds[i] == "USA" {
-					tds[i] = "United States"
-				}
-			}
+	aliases, err := s.store.Country.GetAllAliasBySource(ctx, s.source.Name())
 
-			var countryID uuid.UUID
-			for _, country := range countries {
+	if err != nil {
+		return nil, err
+	}
 
-				if strings.EqualFold(country.Name, tds[1]) == true {
-					countryID = country.Id
-				}
+	countryIds := make(map[string]uuid.UUID)
 
-			}
+	for _, country := range countries {
+		countryIds[strings.ToLower(country.Name)] = country.Id
+	}
 
-			data := models.CoronaModel{
-				CountryId:      countryID,
-				TotalCases:     tds[2],
-				NewCases:       tds[3],
-				TotalDeaths:    tds[4],
-				NewDeaths:      tds[5],
-				TotalRecovered: tds[6],
-				ActiveCases:    tds[8],
-				SeriousCases:   tds[9],
-				TotalTests:     tds[12],
-				Population:     tds[14],
-			}
+	for _, alias := range aliases {
+		countryIds[strings.ToLower(alias.Alias)] = alias.CountryId
+	}
 
-			if data.CountryId != uuid.Nil {
-				datas = append(datas, data)
-			}
+	return countryIds, nil
+}
 
-		})
+// quarantineRecord queues a record that matched no country for review, so it can
+// be mapped to one instead of being dropped.
+func (s *Scrapper) quarantineRecord(ctx context.Context, record Record, scrapedAt time.Time) error {
 
-	})
+	if record.Country == "" {
+		return nil
+	}
 
-	c.Visit("https://www.worldometers.info/coronavirus")
+	row := models.UnmatchedScrapeRowModel{
+		Source:    s.source.Name(),
+		Name:      record.Country,
+		Cells:     record.Cells,
+		ScrapedAt: scrapedAt,
+		CreatedBy: uuid.NewV4(),
+	}
 
-	return datas, nil
+	if row.Cells == nil {
+		row.Cells = []string{}
+	}
+
+	if row.ScrapedAt.IsZero() {
+		row.ScrapedAt = time.Now()
+	}
+
+	err := s.store.Country.UpsertUnmatchedRow(ctx, &row)
+
+	if err != nil {
+		return err
+	}
+
+	s.logger.Out.WithField("source", row.Source).WithField("name", row.Name).
+		Println("Unmatched row quarantined.")
+
+	return nil
+}
+
+// parseCount turns a display figure like "+1,234" into a number. Empty cells and
+// markers like "N/A" are unknown and come back as null rather than zero.
+func parseCount(value string) sql.NullInt64 {
+
+	value = strings.NewReplacer(",", "", "+", "", " ", "").Replace(strings.TrimSpace(value))
+
+	number, err := strconv.ParseInt(value, 10, 64)
+
+	if err != nil {
+		return sql.NullInt64{}
+	}
 
+	return sql.NullInt64{Int64: number, Valid: true}
 }
@@ -1,5 +1,140 @@
 package scrapper
 
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"corona/storage"
+	"database/sql"
+	"testing"
+)
+
+// fakeSource serves fixed records.
+type fakeSource struct {
+	records []Record
+}
+
+func (s fakeSource) Name() string {
+	return "fake"
+}
+
+func (s fakeSource) Capabilities() Capability {
+	return CapabilityCountries
+}
+
+func (s fakeSource) Fetch(ctx context.Context) (Dataset, error) {
+	return Dataset{Records: s.records}, nil
+}
+
+func TestParseCount(t *testing.T) {
+
+	tests := []struct {
+		value string
+		want  sql.NullInt64
+	}{
+		{"2,045,549", sql.NullInt64{Int64: 2045549, Valid: true}},
+		{"+1,234", sql.NullInt64{Int64: 1234, Valid: true}},
+		{" 0 ", sql.NullInt64{Int64: 0, Valid: true}},
+		{"-12", sql.NullInt64{Int64: -12, Valid: true}},
+		{"", sql.NullInt64{}},
+		{"N/A", sql.NullInt64{}},
+	}
+
+	for _, test := range tests {
+		if got := parseCount(test.value); got != test.want {
+			t.Errorf("parseCount(%q) = %+v, want %+v", test.value, got, test.want)
+		}
+	}
+}
+
+func TestWorldSummaryFallback(t *testing.T) {
+
+	s := Scrapper{source: &Worldometers{}}
+
+	summary := s.worldSummary(Dataset{
+		Records: []Record{
+			{Country: "USA", TotalCases: "2,045,549", TotalDeaths: "114,148", TotalRecovered: "N/A"},
+			{Country: "Brazil", TotalCases: "775,184", TotalDeaths: "39,797", TotalRecovered: ""},
+		},
+	})
+
+	if !summary.IsEstimated {
+		t.Error("expected a summed summary to be flagged as estimated")
+	}
+
+	if summary.TotalCases.Int64 != 2820733 || summary.TotalDeaths.Int64 != 153945 {
+		t.Errorf("unexpected totals: %+v", summary)
+	}
+
+	if summary.TotalRecovered.Valid {
+		t.Errorf("expected unknown recovered total, got %d", summary.TotalRecovered.Int64)
+	}
+}
+
+// TestGetCoronaDataAliases checks that scraped names are matched to countries
+// through the aliases of the source, and the global ones, ignoring case.
+func TestGetCoronaDataAliases(t *testing.T) {
+
+	ctx := context.Background()
+	store := storage.NewMemory()
+
+	continent := models.ContinentModel{Name: "Europe"}
+	if err := store.Continent.Insert(ctx, &continent); err != nil {
+		t.Fatal(err)
+	}
+
+	countries := make(map[string]models.CountryModel)
+	for _, name := range []string{"United Kingdom", "South Korea", "France"} {
+		country := models.CountryModel{ContinentId: continent.Id, Name: name}
+		if err := store.Country.Insert(ctx, &country); err != nil {
+			t.Fatal(err)
+		}
+		countries[name] = country
+	}
+
+	for _, alias := range []models.CountryAliasModel{
+		{CountryId: countries["United Kingdom"].Id, Alias: "UK", Source: "fake"},
+		{CountryId: countries["South Korea"].Id, Alias: "S. Korea", Source: ""},
+		{CountryId: countries["France"].Id, Alias: "Narnia", Source: "other"},
+	} {
+		alias := alias
+		if err := store.Country.InsertAlias(ctx, &alias); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := Scrapper{store: store, logger: helpers.NewLogger(), source: fakeSource{records: []Record{
+		{Country: "uk", TotalCases: "10"},
+		{Country: "S. Korea", TotalCases: "20"},
+		{Country: "France", TotalCases: "30"},
+		{Country: "Narnia", TotalCases: "40"},
+	}}}
+
+	data, err := s.GetCoronaData(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"United Kingdom", "South Korea", "France"}
+	if len(data.Countries) != len(want) {
+		t.Fatalf("got %d matched rows, want %d", len(data.Countries), len(want))
+	}
+
+	for i, name := range want {
+		if data.Countries[i].CountryId != countries[name].Id {
+			t.Errorf("row %d matched %s, want %s", i, data.Countries[i].CountryId, name)
+		}
+	}
+
+	rows, err := store.Country.GetAllUnmatchedRow(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].Name != "Narnia" {
+		t.Errorf("unmatched rows = %+v, want Narnia only", rows)
+	}
+}
+
 //func TestGetCoronaData(t *testing.T) {
 //
 //	c := colly.NewCollector(
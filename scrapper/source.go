@@ -0,0 +1,80 @@
+package scrapper
+
+import (
+	"context"
+	"corona/helpers"
+	"github.com/pkg/errors"
+	"time"
+)
+
+type (
+	// Capability flags the data a Source is able to provide.
+	Capability uint
+
+	// Record is a single country row as published by a Source, before it is
+	// matched against the country table. Cells holds the row as it was scraped.
+	Record struct {
+		Country        string
+		TotalCases     string
+		NewCases       string
+		TotalDeaths    string
+		NewDeaths      string
+		TotalRecovered string
+		ActiveCases    string
+		SeriousCases   string
+		TotalTests     string
+		Population     string
+		Cells          []string
+	}
+
+	// WorldRecord is the global counters as published by a Source.
+	WorldRecord struct {
+		TotalCases     string
+		TotalDeaths    string
+		TotalRecovered string
+	}
+
+	// Dataset is everything a Source returned for one fetch. World is only set by
+	// sources with CapabilityWorldTotals.
+	Dataset struct {
+		Records   []Record
+		World     *WorldRecord
+		FetchedAt time.Time
+	}
+
+	// Source is a provider of corona figures, e.g. worldometers.
+	Source interface {
+		Name() string
+		Capabilities() Capability
+		Fetch(ctx context.Context) (Dataset, error)
+	}
+
+	SourceFactory func(opt map[string]interface{}, logger *helpers.Logger) (Source, error)
+)
+
+const (
+	CapabilityCountries Capability = 1 << iota
+	CapabilityTests
+	CapabilityPopulation
+	CapabilityWorldTotals
+)
+
+var mapSources = map[string]SourceFactory{
+	sourceWorldometers: NewWorldometers,
+}
+
+func (c Capability) Has(capability Capability) bool {
+	return c&capability == capability
+}
+
+// NewSource builds the source registered under name, configured with opt.
+func NewSource(name string, opt map[string]interface{}, logger *helpers.Logger) (Source, error) {
+
+	factory, ok := mapSources[name]
+
+	if !ok {
+		return nil, errors.Errorf("unknown data source %q", name)
+	}
+
+	return factory(opt, logger)
+}
@@ -0,0 +1,219 @@
+package scrapper
+
+import (
+	"context"
+	"corona/helpers"
+	"github.com/gocolly/colly/v2"
+	"github.com/pkg/errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type (
+	Worldometers struct {
+		Url    string
+		logger *helpers.Logger
+	}
+
+	worldometersColumn struct {
+		label string
+		set   func(record *Record, value string)
+	}
+)
+
+const (
+	sourceWorldometers = "worldometers"
+
+	worldometersUrl     = "https://www.worldometers.info/coronavirus"
+	worldometersTable   = "table#main_table_countries_today"
+	worldometersCounter = "div#maincounter-wrap"
+)
+
+// worldometersColumns maps the normalized thead labels of the countries table
+// to Record fields. Every one of them has to be present on the page.
+var worldometersColumns = []worldometersColumn{
+	{label: "countryother", set: func(r *Record, v string) { r.Country = v }},
+	{label: "totalcases", set: func(r *Record, v string) { r.TotalCases = v }},
+	{label: "newcases", set: func(r *Record, v string) { r.NewCases = v }},
+	{label: "totaldeaths", set: func(r *Record, v string) { r.TotalDeaths = v }},
+	{label: "newdeaths", set: func(r *Record, v string) { r.NewDeaths = v }},
+	{label: "totalrecovered", set: func(r *Record, v string) { r.TotalRecovered = v }},
+	{label: "activecases", set: func(r *Record, v string) { r.ActiveCases = v }},
+	{label: "seriouscritical", set: func(r *Record, v string) { r.SeriousCases = v }},
+	{label: "totaltests", set: func(r *Record, v string) { r.TotalTests = v }},
+	{label: "population", set: func(r *Record, v string) { r.Population = v }},
+}
+
+// worldometersCounters maps the normalized titles of the main counters to
+// WorldRecord fields.
+var worldometersCounters = map[string]func(w *WorldRecord, v string){
+	"coronaviruscases": func(w *WorldRecord, v string) { w.TotalCases = v },
+	"deaths":           func(w *WorldRecord, v string) { w.TotalDeaths = v },
+	"recovered":        func(w *WorldRecord, v string) { w.TotalRecovered = v },
+}
+
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+func NewWorldometers(opt map[string]interface{}, logger *helpers.Logger) (Source, error) {
+
+	source := &Worldometers{
+		Url:    worldometersUrl,
+		logger: logger,
+	}
+
+	if u, ok := opt["url"].(string); ok && u != "" {
+		source.Url = u
+	}
+
+	return source, nil
+}
+
+func (s Worldometers) Name() string {
+	return sourceWorldometers
+}
+
+func (s Worldometers) Capabilities() Capability {
+	return CapabilityCountries | CapabilityTests | CapabilityPopulation | CapabilityWorldTotals
+}
+
+func (s Worldometers) Fetch(ctx context.Context) (Dataset, error) {
+
+	if err := ctx.Err(); err != nil {
+		return Dataset{}, err
+	}
+
+	u, err := url.Parse(s.Url)
+
+	if err != nil {
+		return Dataset{}, err
+	}
+
+	c := colly.NewCollector(
+		colly.AllowedDomains(u.Hostname()),
+	)
+
+	var (
+		dataset  Dataset
+		world    WorldRecord
+		counters int
+		found    bool
+		tableErr error
+	)
+
+	c.OnRequest(func(r *colly.Request) {
+		s.logger.Out.WithField("source", sourceWorldometers).Println("visiting", r.URL.String())
+	})
+
+	c.OnHTML(worldometersCounter, func(e *colly.HTMLElement) {
+
+		set, ok := worldometersCounters[normalizeLabel(e.ChildText("h1"))]
+
+		if !ok {
+			return
+		}
+
+		set(&world, strings.TrimSpace(e.ChildText(".maincounter-number")))
+		counters++
+
+	})
+
+	c.OnHTML(worldometersTable, func(e *colly.HTMLElement) {
+
+		found = true
+
+		indexes, err := worldometersColumnIndexes(e.ChildTexts("thead th"))
+
+		if err != nil {
+			tableErr = err
+			return
+		}
+
+		e.ForEach("tbody tr", func(_ int, el *colly.HTMLElement) {
+
+			// Continent and world rows are totals, not countries.
+			if strings.Contains(el.Attr("class"), "total_row") {
+				return
+			}
+
+			tds := el.ChildTexts("td")
+
+			record := Record{
+				Cells: tds,
+			}
+			for i, column := range worldometersColumns {
+
+				if indexes[i] >= len(tds) {
+					return
+				}
+
+				column.set(&record, strings.TrimSpace(tds[indexes[i]]))
+			}
+
+			dataset.Records = append(dataset.Records, record)
+
+		})
+
+	})
+
+	err = c.Visit(s.Url)
+
+	if err != nil {
+		return Dataset{}, err
+	}
+
+	dataset.FetchedAt = time.Now()
+
+	if counters == len(worldometersCounters) {
+		dataset.World = &world
+	}
+
+	if tableErr != nil {
+		return Dataset{}, tableErr
+	}
+
+	if !found {
+		return Dataset{}, errors.Errorf("%s: %s not found on %s", sourceWorldometers, worldometersTable, s.Url)
+	}
+
+	return dataset, nil
+
+}
+
+// worldometersColumnIndexes resolves the position of every worldometersColumns
+// entry from the table header labels.
+func worldometersColumnIndexes(labels []string) ([]int, error) {
+
+	positions := make(map[string]int)
+	for i, label := range labels {
+		key := normalizeLabel(label)
+		if _, ok := positions[key]; !ok {
+			positions[key] = i
+		}
+	}
+
+	var missing []string
+	indexes := make([]int, len(worldometersColumns))
+	for i, column := range worldometersColumns {
+
+		position, ok := positions[column.label]
+		if !ok {
+			missing = append(missing, column.label)
+			continue
+		}
+
+		indexes[i] = position
+	}
+
+	if len(missing) > 0 {
+		return nil, errors.Errorf("%s: required column(s) missing from %s header: %s",
+			sourceWorldometers, worldometersTable, strings.Join(missing, ", "))
+	}
+
+	return indexes, nil
+}
+
+func normalizeLabel(label string) string {
+	return nonAlphanumeric.ReplaceAllString(strings.ToLower(label), "")
+}
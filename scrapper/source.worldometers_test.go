@@ -0,0 +1,119 @@
+package scrapper
+
+import (
+	"context"
+	"corona/helpers"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newFixtureServer(t *testing.T, fixture string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, fixture)
+	}))
+}
+
+func TestWorldometersFetch(t *testing.T) {
+
+	server := newFixtureServer(t, "testdata/worldometers.html")
+	defer server.Close()
+
+	source, err := NewSource(sourceWorldometers, map[string]interface{}{"url": server.URL}, helpers.NewLogger())
+	if err != nil {
+		t.Fatalf("NewSource: %v", err)
+	}
+
+	dataset, err := source.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if len(dataset.Records) != 3 {
+		t.Fatalf("got %d records, want 3", len(dataset.Records))
+	}
+
+	usa := dataset.Records[0]
+	if usa.Country != "USA" || usa.TotalCases != "2,045,549" || usa.NewDeaths != "+60" ||
+		usa.ActiveCases != "1,142,539" || usa.TotalTests != "21,715,064" || usa.Population != "330,980,282" {
+		t.Errorf("unexpected record: %+v", usa)
+	}
+
+	if len(usa.Cells) != 16 || usa.Cells[1] != "USA" {
+		t.Errorf("unexpected raw cells: %v", usa.Cells)
+	}
+
+	if dataset.World == nil {
+		t.Fatal("expected the main counters to be read")
+	}
+
+	world := *dataset.World
+	if world.TotalCases != "7,274,502" || world.TotalDeaths != "411,546" || world.TotalRecovered != "3,577,887" {
+		t.Errorf("unexpected world counters: %+v", world)
+	}
+}
+
+func TestWorldometersFetchReorderedColumns(t *testing.T) {
+
+	server := newFixtureServer(t, "testdata/worldometers_reordered.html")
+	defer server.Close()
+
+	source, _ := NewWorldometers(map[string]interface{}{"url": server.URL}, helpers.NewLogger())
+
+	dataset, err := source.Fetch(context.Background())
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+
+	if len(dataset.Records) != 1 {
+		t.Fatalf("got %d records, want 1", len(dataset.Records))
+	}
+
+	if dataset.World != nil {
+		t.Errorf("expected no world counters, got %+v", *dataset.World)
+	}
+
+	usa := dataset.Records[0]
+	if usa.TotalCases != "2,045,549" || usa.TotalDeaths != "114,148" || usa.TotalTests != "21,715,064" ||
+		usa.Population != "330,980,282" || usa.SeriousCases != "16,923" {
+		t.Errorf("unexpected record: %+v", usa)
+	}
+}
+
+func TestWorldometersFetchMissingColumn(t *testing.T) {
+
+	server := newFixtureServer(t, "testdata/worldometers_missing.html")
+	defer server.Close()
+
+	source, _ := NewWorldometers(map[string]interface{}{"url": server.URL}, helpers.NewLogger())
+
+	_, err := source.Fetch(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "totaldeaths") {
+		t.Errorf("expected a missing totaldeaths column error, got %v", err)
+	}
+}
+
+func TestWorldometersFetchError(t *testing.T) {
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	source, err := NewSource(sourceWorldometers, map[string]interface{}{"url": server.URL}, helpers.NewLogger())
+	if err != nil {
+		t.Fatalf("NewSource: %v", err)
+	}
+
+	if _, err := source.Fetch(context.Background()); err == nil {
+		t.Error("expected an error for a failing upstream")
+	}
+}
+
+func TestNewSourceUnknown(t *testing.T) {
+
+	if _, err := NewSource("nope", nil, helpers.NewLogger()); err == nil {
+		t.Error("expected an error for an unknown source")
+	}
+}
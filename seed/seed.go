@@ -0,0 +1,242 @@
+// Package seed holds the reference data the database is bootstrapped with: the
+// continents, the ISO 3166 countries and the names sources publish for them.
+// The country list is derived from the MIT licensed gountries dataset.
+package seed
+
+import (
+	"context"
+	"corona/models"
+	"database/sql"
+	"embed"
+	"encoding/csv"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"strings"
+)
+
+type (
+	Continent struct {
+		Code string
+		Name string
+	}
+
+	// Country is an ISO 3166-1 entry. Continent is the code of its continent.
+	Country struct {
+		Alpha2    string
+		Alpha3    string
+		Numeric   string
+		Name      string
+		Continent string
+	}
+
+	// Alias is a name a source publishes for the country with code Alpha2.
+	Alias struct {
+		Alpha2 string
+		Alias  string
+		Source string
+	}
+
+	Summary struct {
+		Continents models.UpsertSummary `json:"continents"`
+		Countries  models.UpsertSummary `json:"countries"`
+		Aliases    models.UpsertSummary `json:"aliases"`
+	}
+)
+
+//go:embed *.csv
+var files embed.FS
+
+func Continents() ([]Continent, error) {
+
+	records, err := readCSV("continents.csv", 2)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var continents []Continent
+	for _, record := range records {
+		continents = append(continents, Continent{
+			Code: record[0],
+			Name: record[1],
+		})
+	}
+
+	return continents, nil
+}
+
+func Countries() ([]Country, error) {
+
+	records, err := readCSV("countries.csv", 5)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var countries []Country
+	for _, record := range records {
+		countries = append(countries, Country{
+			Alpha2:    record[0],
+			Alpha3:    record[1],
+			Numeric:   record[2],
+			Name:      record[3],
+			Continent: record[4],
+		})
+	}
+
+	return countries, nil
+}
+
+func Aliases() ([]Alias, error) {
+
+	records, err := readCSV("aliases.csv", 3)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var aliases []Alias
+	for _, record := range records {
+		aliases = append(aliases, Alias{
+			Alpha2: record[0],
+			Alias:  record[1],
+			Source: record[2],
+		})
+	}
+
+	return aliases, nil
+}
+
+// Run upserts the embedded continents, countries and aliases. It can be run any
+// number of times: rows that are already up to date are left untouched.
+func Run(ctx context.Context, db *sql.DB) (Summary, error) {
+
+	var summary Summary
+
+	continents, err := Continents()
+
+	if err != nil {
+		return summary, err
+	}
+
+	countries, err := Countries()
+
+	if err != nil {
+		return summary, err
+	}
+
+	aliases, err := Aliases()
+
+	if err != nil {
+		return summary, err
+	}
+
+	createdBy := uuid.NewV4()
+
+	continentIds := make(map[string]uuid.UUID)
+	for _, continent := range continents {
+
+		model := models.ContinentModel{
+			Name:      continent.Name,
+			Code:      continent.Code,
+			CreatedBy: createdBy,
+		}
+
+		result, err := model.Upsert(ctx, db)
+
+		if err != nil {
+			return summary, errors.Wrapf(err, "seed: continent %s", continent.Code)
+		}
+
+		summary.Continents.Add(result)
+		continentIds[continent.Code] = model.Id
+	}
+
+	countryIds := make(map[string]uuid.UUID)
+	for _, country := range countries {
+
+		continentId, ok := continentIds[country.Continent]
+
+		if !ok {
+			return summary, errors.Errorf("seed: country %s: unknown continent %s", country.Alpha2, country.Continent)
+		}
+
+		model := models.CountryModel{
+			ContinentId: continentId,
+			Name:        country.Name,
+			Code:        country.Alpha2,
+			Alpha3:      country.Alpha3,
+			NumericCode: country.Numeric,
+			CreatedBy:   createdBy,
+		}
+
+		result, err := model.Upsert(ctx, db)
+
+		if err != nil {
+			return summary, errors.Wrapf(err, "seed: country %s", country.Alpha2)
+		}
+
+		summary.Countries.Add(result)
+		countryIds[country.Alpha2] = model.Id
+	}
+
+	for _, alias := range aliases {
+
+		countryId, ok := countryIds[alias.Alpha2]
+
+		if !ok {
+			return summary, errors.Errorf("seed: alias %q: unknown country %s", alias.Alias, alias.Alpha2)
+		}
+
+		model := models.CountryAliasModel{
+			CountryId: countryId,
+			Alias:     alias.Alias,
+			Source:    alias.Source,
+			CreatedBy: createdBy,
+		}
+
+		result, err := model.Upsert(ctx, db)
+
+		if err != nil {
+			return summary, errors.Wrapf(err, "seed: alias %q", alias.Alias)
+		}
+
+		summary.Aliases.Add(result)
+	}
+
+	return summary, nil
+}
+
+// readCSV returns the rows of an embedded file, header excluded, checking each
+// has the expected number of columns.
+func readCSV(name string, columns int) ([][]string, error) {
+
+	file, err := files.Open(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = columns
+
+	records, err := reader.ReadAll()
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "seed: %s", name)
+	}
+
+	if len(records) == 0 {
+		return nil, errors.Errorf("seed: %s is empty", name)
+	}
+
+	for _, record := range records {
+		for i := range record {
+			record[i] = strings.TrimSpace(record[i])
+		}
+	}
+
+	return records[1:], nil
+}
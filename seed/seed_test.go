@@ -0,0 +1,61 @@
+package seed
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestDataset(t *testing.T) {
+
+	continents, err := Continents()
+	if err != nil {
+		t.Fatalf("Continents: %v", err)
+	}
+
+	countries, err := Countries()
+	if err != nil {
+		t.Fatalf("Countries: %v", err)
+	}
+
+	aliases, err := Aliases()
+	if err != nil {
+		t.Fatalf("Aliases: %v", err)
+	}
+
+	continentCodes := make(map[string]bool)
+	for _, continent := range continents {
+		continentCodes[continent.Code] = true
+	}
+
+	alpha2 := regexp.MustCompile(`^[A-Z]{2}$`)
+	alpha3 := regexp.MustCompile(`^[A-Z]{3}$`)
+	numeric := regexp.MustCompile(`^[0-9]{3}$`)
+
+	countryCodes := make(map[string]bool)
+	for _, country := range countries {
+		if !alpha2.MatchString(country.Alpha2) || !alpha3.MatchString(country.Alpha3) ||
+			!numeric.MatchString(country.Numeric) {
+			t.Errorf("country %+v: malformed ISO codes", country)
+		}
+		if country.Name == "" {
+			t.Errorf("country %s: missing name", country.Alpha2)
+		}
+		if !continentCodes[country.Continent] {
+			t.Errorf("country %s: unknown continent %s", country.Alpha2, country.Continent)
+		}
+		if countryCodes[country.Alpha2] {
+			t.Errorf("country %s: duplicated", country.Alpha2)
+		}
+		countryCodes[country.Alpha2] = true
+	}
+
+	if len(countries) < 249 {
+		t.Errorf("got %d countries, want the 249 ISO 3166-1 entries", len(countries))
+	}
+
+	for _, alias := range aliases {
+		if !countryCodes[alias.Alpha2] {
+			t.Errorf("alias %q: unknown country %s", alias.Alias, alias.Alpha2)
+		}
+	}
+}
@@ -0,0 +1,556 @@
+package storage
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"database/sql"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type memoryCorona struct {
+	m *memory
+}
+
+// coronaDetail joins the figures with their country and continent. The caller
+// holds m.mu.
+func (m *memory) coronaDetail(data models.CoronaModel) (models.CoronaDetailModel, error) {
+
+	country, ok := m.countries[data.CountryId]
+
+	if !ok {
+		return models.CoronaDetailModel{}, sql.ErrNoRows
+	}
+
+	detail, err := m.countryDetail(country)
+
+	if err != nil {
+		return models.CoronaDetailModel{}, err
+	}
+
+	return models.CoronaDetailModel{Corona: data, Country: detail}, nil
+}
+
+func (s memoryCorona) GetByCountry(ctx context.Context, country string) (models.CoronaDetailModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	for _, data := range s.m.corona {
+
+		detail, err := s.m.coronaDetail(data)
+
+		if err == nil && strings.EqualFold(detail.Country.Country.Name, country) {
+			return detail, nil
+		}
+	}
+
+	return models.CoronaDetailModel{}, sql.ErrNoRows
+}
+
+func (s memoryCorona) GetAll(ctx context.Context, filter helpers.Filter) ([]models.CoronaDetailModel, error) {
+	return s.list(filter, keepAll)
+}
+
+func (s memoryCorona) GetAllByContinent(ctx context.Context, filter helpers.Filter, continentId uuid.UUID) (
+	[]models.CoronaDetailModel, error) {
+
+	filter.Search = ""
+
+	return s.list(filter, keepContinent(continentId))
+}
+
+func (s memoryCorona) GetAllByCountries(ctx context.Context, countryIds []uuid.UUID) (
+	[]models.CoronaDetailModel, error) {
+
+	ids := make(map[uuid.UUID]bool)
+	for _, id := range countryIds {
+		ids[id] = true
+	}
+
+	filter := helpers.Filter{FilterOption: helpers.FilterOption{Limit: len(countryIds)}}
+
+	return s.list(filter, func(detail models.CoronaDetailModel) bool {
+		return ids[detail.Country.Country.Id]
+	})
+}
+
+func (s memoryCorona) Count(ctx context.Context, filter helpers.Filter) (int, error) {
+
+	datas, err := s.filter(filter, keepAll)
+
+	return len(datas), err
+}
+
+func (s memoryCorona) CountByContinent(ctx context.Context, filter helpers.Filter, continentId uuid.UUID) (int, error) {
+
+	filter.Search = ""
+
+	datas, err := s.filter(filter, keepContinent(continentId))
+
+	return len(datas), err
+}
+
+func keepAll(detail models.CoronaDetailModel) bool {
+	return true
+}
+
+func keepContinent(continentId uuid.UUID) func(models.CoronaDetailModel) bool {
+	return func(detail models.CoronaDetailModel) bool {
+		return detail.Country.Continent.Id == continentId
+	}
+}
+
+// list returns the page filter asks for of the figures keep accepts.
+func (s memoryCorona) list(filter helpers.Filter, keep func(models.CoronaDetailModel) bool) (
+	[]models.CoronaDetailModel, error) {
+
+	if err := checkCursor(filter); err != nil {
+		return nil, err
+	}
+
+	datas, err := s.filter(filter, keep)
+
+	if err != nil {
+		return nil, err
+	}
+
+	sortCorona(datas, filter.Sort, strings.EqualFold(filter.Dir, "desc"))
+
+	if filter.Cursor != nil {
+
+		var after []models.CoronaDetailModel
+		for _, detail := range datas {
+			if afterCursor(detail, filter) {
+				after = append(after, detail)
+			}
+		}
+		datas = after
+	}
+
+	start, end := window(len(datas), filter)
+
+	return datas[start:end], nil
+}
+
+// filter returns the figures keep accepts that match filter, unsorted.
+func (s memoryCorona) filter(filter helpers.Filter, keep func(models.CoronaDetailModel) bool) (
+	[]models.CoronaDetailModel, error) {
+
+	if filter.Sort != "" && !models.IsCoronaSort(filter.Sort) {
+		return nil, errors.Wrapf(helpers.ErrInvalidFilter, "unknown sort %q", filter.Sort)
+	}
+
+	for _, r := range filter.Ranges {
+		if !models.IsRankingMetric(r.Field) {
+			return nil, errors.Wrapf(helpers.ErrInvalidFilter, "unknown filter %q", r.Field+"_"+r.Op)
+		}
+	}
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var datas []models.CoronaDetailModel
+	for _, data := range s.m.corona {
+
+		detail, err := s.m.coronaDetail(data)
+
+		if err != nil || !keep(detail) || !matches(detail.Country.Country.Name, filter) || !matchesCorona(detail, filter) {
+			continue
+		}
+
+		datas = append(datas, detail)
+	}
+
+	return datas, nil
+}
+
+// afterCursor reports whether detail sorts after the row the cursor of filter
+// points at, by the value of the sort and then the country id.
+func afterCursor(detail models.CoronaDetailModel, filter helpers.Filter) bool {
+
+	cursor := filter.Cursor
+	desc := strings.EqualFold(filter.Dir, "desc")
+	name := detail.Country.Country.Name
+	id := detail.Country.Country.Id.String()
+
+	if filter.Sort == "" || filter.Sort == models.SortName {
+		return cursor.Value != nil &&
+			(name != *cursor.Value && (name > *cursor.Value) != desc || name == *cursor.Value && id > cursor.Key)
+	}
+
+	value := detail.Corona.MetricValue(filter.Sort)
+
+	if cursor.Value == nil {
+		return value == nil && id > cursor.Key
+	}
+
+	last, err := strconv.ParseFloat(*cursor.Value, 64)
+
+	switch {
+	case err != nil:
+		return false
+	case value == nil:
+		return true
+	case *value == last:
+		return id > cursor.Key
+	}
+
+	return (*value > last) != desc
+}
+
+// matchesCorona reports whether detail passes the attribute and range filters,
+// as the Postgres queries apply them: unknown values never pass a bound.
+func matchesCorona(detail models.CoronaDetailModel, filter helpers.Filter) bool {
+
+	continent := detail.Country.Continent
+	if filter.Continent != "" &&
+		!strings.EqualFold(continent.Name, filter.Continent) && !strings.EqualFold(continent.Code, filter.Continent) {
+		return false
+	}
+
+	if !filter.UpdatedSince.IsZero() {
+
+		updated := detail.Corona.CreatedAt
+		if detail.Corona.UpdatedAt.Valid {
+			updated = detail.Corona.UpdatedAt.Time
+		}
+
+		if updated.Before(filter.UpdatedSince) {
+			return false
+		}
+	}
+
+	for _, r := range filter.Ranges {
+
+		value := detail.Corona.MetricValue(r.Field)
+
+		if value == nil || (r.Op == helpers.RangeGte && *value < r.Value) || (r.Op == helpers.RangeLte && *value > r.Value) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// sortCorona orders datas the way the Postgres queries do: by the value named by
+// key, unknown values last, then by country id.
+func sortCorona(datas []models.CoronaDetailModel, key string, desc bool) {
+
+	if key == "" {
+		key = models.SortName
+	}
+
+	sort.SliceStable(datas, func(i, j int) bool {
+
+		a, b := datas[i], datas[j]
+		nameA, nameB := a.Country.Country.Name, b.Country.Country.Name
+		idA, idB := a.Country.Country.Id.String(), b.Country.Country.Id.String()
+
+		if key != models.SortName {
+
+			valueA, valueB := a.Corona.MetricValue(key), b.Corona.MetricValue(key)
+
+			switch {
+			case valueA == nil && valueB == nil:
+			case valueA == nil:
+				return false
+			case valueB == nil:
+				return true
+			case *valueA != *valueB:
+				return (*valueA < *valueB) != desc
+			}
+
+			return idA < idB
+		}
+
+		if nameA != nameB {
+			return (nameA < nameB) != desc
+		}
+
+		return idA < idB
+	})
+}
+
+func (s memoryCorona) GetTop(ctx context.Context, metric string, limit int, continentId uuid.NullUUID) (
+	[]models.CoronaDetailModel, error) {
+
+	if !models.IsRankingMetric(metric) {
+		return nil, errors.Wrapf(helpers.ErrInvalidFilter, "unknown ranking metric %q", metric)
+	}
+
+	filter := helpers.Filter{
+		FilterOption: helpers.FilterOption{Limit: limit, Dir: "desc", Sort: metric},
+	}
+
+	return s.list(filter, func(detail models.CoronaDetailModel) bool {
+		return detail.Corona.MetricValue(metric) != nil &&
+			(!continentId.Valid || detail.Country.Continent.Id == continentId.UUID)
+	})
+}
+
+// Insert fails when the country already has figures, as the unique index on
+// corona_data(country_id) does.
+func (s memoryCorona) Insert(ctx context.Context, data *models.CoronaModel) error {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	for _, existing := range s.m.corona {
+		if existing.CountryId == data.CountryId {
+			return errors.Wrapf(helpers.ErrDuplicate, "corona data of country %s already exists", data.CountryId)
+		}
+	}
+
+	data.Id = uuid.NewV4()
+	data.CreatedAt = now()
+	s.m.corona[data.Id] = *data
+
+	return nil
+}
+
+func (s memoryCorona) Upsert(ctx context.Context, data *models.CoronaModel) (models.UpsertResult, error) {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	// Postgres refuses the row through the country foreign key.
+	if _, ok := s.m.countries[data.CountryId]; !ok {
+		return models.UpsertUnchanged, errors.Errorf("country %s does not exist", data.CountryId)
+	}
+
+	for id, existing := range s.m.corona {
+
+		if existing.CountryId != data.CountryId {
+			continue
+		}
+
+		if sameFigures(existing, *data) {
+			return models.UpsertUnchanged, nil
+		}
+
+		updatedBy := data.CreatedBy
+
+		data.Id = existing.Id
+		data.CreatedBy = existing.CreatedBy
+		data.CreatedAt = existing.CreatedAt
+		data.UpdatedBy = uuid.NullUUID{UUID: updatedBy, Valid: true}
+		data.UpdatedAt = pq.NullTime{Time: now(), Valid: true}
+		s.m.corona[id] = *data
+
+		return models.UpsertUpdated, nil
+	}
+
+	data.Id = uuid.NewV4()
+	data.CreatedAt = now()
+	s.m.corona[data.Id] = *data
+
+	return models.UpsertInserted, nil
+}
+
+func sameFigures(a, b models.CoronaModel) bool {
+
+	return a.TotalCases == b.TotalCases &&
+		a.NewCases == b.NewCases &&
+		a.TotalDeaths == b.TotalDeaths &&
+		a.NewDeaths == b.NewDeaths &&
+		a.TotalRecovered == b.TotalRecovered &&
+		a.ActiveCases == b.ActiveCases &&
+		a.SeriousCases == b.SeriousCases &&
+		a.TotalTests == b.TotalTests &&
+		a.Population == b.Population
+}
+
+func (s memoryCorona) GetTotalByContinent(ctx context.Context, continentId uuid.UUID) (
+	models.CoronaTotalModel, error) {
+
+	totals := s.totals(func(continent models.ContinentModel) bool {
+		return continent.Id == continentId
+	})
+
+	if len(totals) == 0 {
+		return models.CoronaTotalModel{}, sql.ErrNoRows
+	}
+
+	return totals[0], nil
+}
+
+func (s memoryCorona) GetAllTotal(ctx context.Context) ([]models.CoronaTotalModel, error) {
+
+	return s.totals(func(continent models.ContinentModel) bool {
+		return true
+	}), nil
+}
+
+func (s memoryCorona) totals(keep func(continent models.ContinentModel) bool) []models.CoronaTotalModel {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	byContinent := make(map[uuid.UUID]*models.CoronaTotalModel)
+	for id, continent := range s.m.continents {
+		if keep(continent) {
+			byContinent[id] = &models.CoronaTotalModel{Continent: continent}
+		}
+	}
+
+	for _, data := range s.m.corona {
+
+		country, ok := s.m.countries[data.CountryId]
+
+		if !ok {
+			continue
+		}
+
+		total, ok := byContinent[country.ContinentId]
+
+		if !ok {
+			continue
+		}
+
+		total.Countries++
+		addNull(&total.TotalCases, data.TotalCases)
+		addNull(&total.TotalDeaths, data.TotalDeaths)
+		addNull(&total.TotalRecovered, data.TotalRecovered)
+		addNull(&total.ActiveCases, data.ActiveCases)
+		addNull(&total.TotalTests, data.TotalTests)
+		addNull(&total.Population, data.Population)
+	}
+
+	var totals []models.CoronaTotalModel
+	for _, total := range byContinent {
+		totals = append(totals, *total)
+	}
+
+	sort.Slice(totals, func(i, j int) bool {
+		return totals[i].Continent.Name < totals[j].Continent.Name
+	})
+
+	return totals
+}
+
+// addNull sums like SQL's SUM: unknown values are skipped and the sum is only
+// unknown when every value is.
+func addNull(sum *sql.NullInt64, value sql.NullInt64) {
+
+	if !value.Valid {
+		return
+	}
+
+	sum.Int64 += value.Int64
+	sum.Valid = true
+}
+
+func (s memoryCorona) GetAllSnapshotByCountry(ctx context.Context, countryId uuid.UUID, from, to time.Time) (
+	[]models.CoronaSnapshotModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var snapshots []models.CoronaSnapshotModel
+	for _, snapshot := range s.m.snapshots {
+		if snapshot.CountryId == countryId && !snapshot.ReportDate.Before(from) && !snapshot.ReportDate.After(to) {
+			snapshots = append(snapshots, snapshot)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		return snapshots[i].ReportDate.Before(snapshots[j].ReportDate)
+	})
+
+	return snapshots, nil
+}
+
+func (s memoryCorona) UpsertSnapshot(ctx context.Context, snapshot *models.CoronaSnapshotModel) error {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	snapshot.ReportDate = reportDate(snapshot.ReportDate)
+
+	for id, existing := range s.m.snapshots {
+
+		if existing.CountryId != snapshot.CountryId || !existing.ReportDate.Equal(snapshot.ReportDate) {
+			continue
+		}
+
+		updatedBy := snapshot.CreatedBy
+
+		snapshot.Id = existing.Id
+		snapshot.CreatedBy = existing.CreatedBy
+		snapshot.CreatedAt = existing.CreatedAt
+		snapshot.UpdatedBy = uuid.NullUUID{UUID: updatedBy, Valid: true}
+		snapshot.UpdatedAt = pq.NullTime{Time: now(), Valid: true}
+		s.m.snapshots[id] = *snapshot
+
+		return nil
+	}
+
+	snapshot.Id = uuid.NewV4()
+	snapshot.CreatedAt = now()
+	s.m.snapshots[snapshot.Id] = *snapshot
+
+	return nil
+}
+
+func (s memoryCorona) GetLatestWorldSummary(ctx context.Context) (models.WorldSummaryModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var (
+		latest models.WorldSummaryModel
+		found  bool
+	)
+	for _, summary := range s.m.worldSummary {
+		if !found || summary.ReportDate.After(latest.ReportDate) {
+			latest, found = summary, true
+		}
+	}
+
+	if !found {
+		return models.WorldSummaryModel{}, sql.ErrNoRows
+	}
+
+	return latest, nil
+}
+
+func (s memoryCorona) UpsertWorldSummary(ctx context.Context, summary *models.WorldSummaryModel) error {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	summary.ReportDate = reportDate(summary.ReportDate)
+
+	for id, existing := range s.m.worldSummary {
+
+		if !existing.ReportDate.Equal(summary.ReportDate) {
+			continue
+		}
+
+		summary.Id = existing.Id
+		summary.CreatedAt = existing.CreatedAt
+		summary.CreatedBy = existing.CreatedBy
+		summary.UpdatedAt = pq.NullTime{Time: now(), Valid: true}
+		s.m.worldSummary[id] = *summary
+
+		return nil
+	}
+
+	summary.Id = uuid.NewV4()
+	summary.CreatedAt = now()
+	s.m.worldSummary[summary.Id] = *summary
+
+	return nil
+}
+
+// reportDate truncates t to its day, as stored in the DATE columns.
+func reportDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
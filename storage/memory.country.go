@@ -0,0 +1,318 @@
+package storage
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"database/sql"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"sort"
+	"strings"
+)
+
+type memoryCountry struct {
+	m *memory
+}
+
+// countryDetail joins the country with its continent. The caller holds m.mu.
+func (m *memory) countryDetail(country models.CountryModel) (models.CountryDetailModel, error) {
+
+	continent, ok := m.continents[country.ContinentId]
+
+	if !ok {
+		return models.CountryDetailModel{}, sql.ErrNoRows
+	}
+
+	return models.CountryDetailModel{Country: country, Continent: continent}, nil
+}
+
+func (s memoryCountry) GetOne(ctx context.Context, id uuid.UUID) (models.CountryDetailModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	country, ok := s.m.countries[id]
+
+	if !ok {
+		return models.CountryDetailModel{}, sql.ErrNoRows
+	}
+
+	return s.m.countryDetail(country)
+}
+
+func (s memoryCountry) GetOneByName(ctx context.Context, name string) (models.CountryDetailModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	for _, country := range s.m.countries {
+		if strings.EqualFold(country.Name, name) {
+			return s.m.countryDetail(country)
+		}
+	}
+
+	return models.CountryDetailModel{}, sql.ErrNoRows
+}
+
+func (s memoryCountry) GetOneByCode(ctx context.Context, code string) (models.CountryDetailModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	for _, country := range s.m.countries {
+		if country.Code != "" && strings.EqualFold(country.Code, code) ||
+			country.Alpha3 != "" && strings.EqualFold(country.Alpha3, code) {
+			return s.m.countryDetail(country)
+		}
+	}
+
+	return models.CountryDetailModel{}, sql.ErrNoRows
+}
+
+func (s memoryCountry) GetAll(ctx context.Context, filter helpers.Filter) ([]models.CountryDetailModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var (
+		countries []models.CountryDetailModel
+		names     []string
+		keys      []string
+	)
+	for _, country := range s.m.countries {
+
+		detail, err := s.m.countryDetail(country)
+
+		if err != nil {
+			continue
+		}
+
+		countries = append(countries, detail)
+		names = append(names, country.Name)
+		keys = append(keys, country.Id.String())
+	}
+
+	indexes, err := page(names, keys, filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var paged []models.CountryDetailModel
+	for _, i := range indexes {
+		paged = append(paged, countries[i])
+	}
+
+	return paged, nil
+}
+
+func (s memoryCountry) Count(ctx context.Context, filter helpers.Filter) (int, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var names []string
+	for _, country := range s.m.countries {
+		if _, err := s.m.countryDetail(country); err == nil {
+			names = append(names, country.Name)
+		}
+	}
+
+	return len(matching(names, filter)), nil
+}
+
+func (s memoryCountry) All(ctx context.Context) ([]models.CountryModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var countries []models.CountryModel
+	for _, country := range s.m.countries {
+		countries = append(countries, country)
+	}
+
+	return countries, nil
+}
+
+func (s memoryCountry) Insert(ctx context.Context, country *models.CountryModel) error {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	country.Id = uuid.NewV4()
+	country.CreatedAt = now()
+	s.m.countries[country.Id] = *country
+
+	return nil
+}
+
+func (s memoryCountry) GetOneAlias(ctx context.Context, id uuid.UUID) (models.CountryAliasModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	alias, ok := s.m.aliases[id]
+
+	if !ok {
+		return models.CountryAliasModel{}, sql.ErrNoRows
+	}
+
+	return alias, nil
+}
+
+func (s memoryCountry) GetAllAliasByCountry(ctx context.Context, countryId uuid.UUID) (
+	[]models.CountryAliasModel, error) {
+
+	return s.aliases(func(alias models.CountryAliasModel) bool {
+		return alias.CountryId == countryId
+	}), nil
+}
+
+func (s memoryCountry) GetAllAliasBySource(ctx context.Context, source string) ([]models.CountryAliasModel, error) {
+
+	return s.aliases(func(alias models.CountryAliasModel) bool {
+		return alias.Source == source || alias.Source == ""
+	}), nil
+}
+
+func (s memoryCountry) aliases(keep func(alias models.CountryAliasModel) bool) []models.CountryAliasModel {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var aliases []models.CountryAliasModel
+	for _, alias := range s.m.aliases {
+		if keep(alias) {
+			aliases = append(aliases, alias)
+		}
+	}
+
+	sort.Slice(aliases, func(i, j int) bool {
+		return aliases[i].Alias < aliases[j].Alias
+	})
+
+	return aliases
+}
+
+func (s memoryCountry) InsertAlias(ctx context.Context, alias *models.CountryAliasModel) error {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	for _, existing := range s.m.aliases {
+		if strings.EqualFold(existing.Alias, alias.Alias) && existing.Source == alias.Source {
+			return errors.Wrapf(helpers.ErrDuplicate, "alias %q already exists for source %q", alias.Alias, alias.Source)
+		}
+	}
+
+	alias.Id = uuid.NewV4()
+	alias.CreatedAt = now()
+	s.m.aliases[alias.Id] = *alias
+
+	return nil
+}
+
+func (s memoryCountry) DeleteAlias(ctx context.Context, alias *models.CountryAliasModel) error {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	delete(s.m.aliases, alias.Id)
+
+	return nil
+}
+
+func (s memoryCountry) GetOneUnmatchedRow(ctx context.Context, id uuid.UUID) (models.UnmatchedScrapeRowModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	row, ok := s.m.unmatchedRows[id]
+
+	if !ok {
+		return models.UnmatchedScrapeRowModel{}, sql.ErrNoRows
+	}
+
+	return row, nil
+}
+
+func (s memoryCountry) GetAllUnmatchedRow(ctx context.Context, resolved bool) (
+	[]models.UnmatchedScrapeRowModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var rows []models.UnmatchedScrapeRowModel
+	for _, row := range s.m.unmatchedRows {
+		if resolved || !row.ResolvedAt.Valid {
+			rows = append(rows, row)
+		}
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].ScrapedAt.Equal(rows[j].ScrapedAt) {
+			return rows[i].ScrapedAt.After(rows[j].ScrapedAt)
+		}
+		return rows[i].Name < rows[j].Name
+	})
+
+	return rows, nil
+}
+
+func (s memoryCountry) UpsertUnmatchedRow(ctx context.Context, row *models.UnmatchedScrapeRowModel) error {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	for id, existing := range s.m.unmatchedRows {
+
+		if existing.ResolvedAt.Valid || existing.Source != row.Source || !strings.EqualFold(existing.Name, row.Name) {
+			continue
+		}
+
+		existing.Cells = row.Cells
+		existing.ScrapedAt = row.ScrapedAt
+		existing.UpdatedAt = pq.NullTime{Time: now(), Valid: true}
+		existing.UpdatedBy = uuid.NullUUID{UUID: row.CreatedBy, Valid: true}
+		s.m.unmatchedRows[id] = existing
+
+		row.Id, row.CreatedAt, row.CreatedBy = existing.Id, existing.CreatedAt, existing.CreatedBy
+
+		return nil
+	}
+
+	row.Id = uuid.NewV4()
+	row.CreatedAt = now()
+	s.m.unmatchedRows[row.Id] = *row
+
+	return nil
+}
+
+func (s memoryCountry) ResolveUnmatchedRow(ctx context.Context, row *models.UnmatchedScrapeRowModel) error {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	existing, ok := s.m.unmatchedRows[row.Id]
+
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	resolvedAt := pq.NullTime{Time: now(), Valid: true}
+
+	existing.CountryId = row.CountryId
+	existing.ResolvedBy = row.ResolvedBy
+	existing.ResolvedAt = resolvedAt
+	existing.UpdatedBy = row.ResolvedBy
+	existing.UpdatedAt = resolvedAt
+	s.m.unmatchedRows[row.Id] = existing
+
+	row.ResolvedAt = resolvedAt
+	row.UpdatedAt = resolvedAt
+	row.UpdatedBy = row.ResolvedBy
+
+	return nil
+}
@@ -0,0 +1,380 @@
+package storage
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"database/sql"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// memory is the data shared by the repositories of a Store from NewMemory.
+	memory struct {
+		mu   sync.RWMutex
+		txMu sync.Mutex
+		memoryData
+	}
+
+	memoryData struct {
+		continents    map[uuid.UUID]models.ContinentModel
+		countries     map[uuid.UUID]models.CountryModel
+		aliases       map[uuid.UUID]models.CountryAliasModel
+		unmatchedRows map[uuid.UUID]models.UnmatchedScrapeRowModel
+		corona        map[uuid.UUID]models.CoronaModel
+		snapshots     map[uuid.UUID]models.CoronaSnapshotModel
+		worldSummary  map[uuid.UUID]models.WorldSummaryModel
+		users         map[uuid.UUID]models.UserModel
+		tokens        map[uuid.UUID]models.TokenModel
+		rateLimits    map[uuid.UUID]models.RateLimitModel
+		subscriptions map[uuid.UUID]models.SubscriptionModel
+	}
+
+	memoryContinent struct {
+		m *memory
+	}
+
+	memorySubscription struct {
+		m *memory
+	}
+)
+
+// NewMemory returns an empty Store kept in memory, for tests. Transactions are
+// serialized and undone by restoring a copy of the data taken when they began.
+func NewMemory() Store {
+
+	m := &memory{
+		memoryData: newMemoryData(),
+	}
+
+	store := Store{
+		Continent:    memoryContinent{m: m},
+		Country:      memoryCountry{m: m},
+		Corona:       memoryCorona{m: m},
+		User:         memoryUser{m: m},
+		Token:        memoryToken{m: m},
+		RateLimit:    memoryRateLimit{m: m},
+		Subscription: memorySubscription{m: m},
+	}
+
+	txStore := store
+	txStore.transaction = func(ctx context.Context, fn func(store Store) error) error {
+		return fn(txStore)
+	}
+
+	store.transaction = func(ctx context.Context, fn func(store Store) error) error {
+
+		m.txMu.Lock()
+		defer m.txMu.Unlock()
+
+		m.mu.RLock()
+		backup := m.memoryData.clone()
+		m.mu.RUnlock()
+
+		err := fn(txStore)
+
+		if err != nil {
+			m.mu.Lock()
+			m.memoryData = backup
+			m.mu.Unlock()
+		}
+
+		return err
+	}
+
+	return store
+}
+
+func (d memoryData) clone() memoryData {
+
+	clone := newMemoryData()
+
+	for id, row := range d.continents {
+		clone.continents[id] = row
+	}
+	for id, row := range d.countries {
+		clone.countries[id] = row
+	}
+	for id, row := range d.aliases {
+		clone.aliases[id] = row
+	}
+	for id, row := range d.unmatchedRows {
+		clone.unmatchedRows[id] = row
+	}
+	for id, row := range d.corona {
+		clone.corona[id] = row
+	}
+	for id, row := range d.snapshots {
+		clone.snapshots[id] = row
+	}
+	for id, row := range d.worldSummary {
+		clone.worldSummary[id] = row
+	}
+	for id, row := range d.users {
+		clone.users[id] = row
+	}
+	for id, row := range d.tokens {
+		clone.tokens[id] = row
+	}
+	for id, row := range d.rateLimits {
+		clone.rateLimits[id] = row
+	}
+	for id, row := range d.subscriptions {
+		clone.subscriptions[id] = row
+	}
+
+	return clone
+}
+
+func newMemoryData() memoryData {
+
+	return memoryData{
+		continents:    make(map[uuid.UUID]models.ContinentModel),
+		countries:     make(map[uuid.UUID]models.CountryModel),
+		aliases:       make(map[uuid.UUID]models.CountryAliasModel),
+		unmatchedRows: make(map[uuid.UUID]models.UnmatchedScrapeRowModel),
+		corona:        make(map[uuid.UUID]models.CoronaModel),
+		snapshots:     make(map[uuid.UUID]models.CoronaSnapshotModel),
+		worldSummary:  make(map[uuid.UUID]models.WorldSummaryModel),
+		users:         make(map[uuid.UUID]models.UserModel),
+		tokens:        make(map[uuid.UUID]models.TokenModel),
+		rateLimits:    make(map[uuid.UUID]models.RateLimitModel),
+		subscriptions: make(map[uuid.UUID]models.SubscriptionModel),
+	}
+}
+
+// page applies the search, direction, cursor, limit and offset of filter the way
+// the Postgres queries do to rows of the given names and unique keys, returning
+// the indexes of the rows to keep in order. They can only be sorted by name and
+// cannot be bounded.
+func page(names, keys []string, filter helpers.Filter) ([]int, error) {
+
+	if filter.Sort != "" && filter.Sort != models.SortName {
+		return nil, errors.Wrapf(helpers.ErrInvalidFilter, "unknown sort %q", filter.Sort)
+	}
+
+	if len(filter.Ranges) > 0 {
+		r := filter.Ranges[0]
+		return nil, errors.Wrapf(helpers.ErrInvalidFilter, "unknown filter %q", r.Field+"_"+r.Op)
+	}
+
+	if err := checkCursor(filter); err != nil {
+		return nil, err
+	}
+
+	matched := matching(names, filter)
+
+	desc := strings.EqualFold(filter.Dir, "desc")
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if names[a] != names[b] {
+			return (names[a] < names[b]) != desc
+		}
+		return keys[a] < keys[b]
+	})
+
+	if cursor := filter.Cursor; cursor != nil {
+
+		var after []int
+		for _, i := range matched {
+			if cursor.Value != nil &&
+				(names[i] != *cursor.Value && (names[i] > *cursor.Value) != desc ||
+					names[i] == *cursor.Value && keys[i] > cursor.Key) {
+				after = append(after, i)
+			}
+		}
+		matched = after
+	}
+
+	start, end := window(len(matched), filter)
+
+	return matched[start:end], nil
+}
+
+// matching returns the indexes of the names matching the search of filter.
+func matching(names []string, filter helpers.Filter) []int {
+
+	var matched []int
+	for i, name := range names {
+		if matches(name, filter) {
+			matched = append(matched, i)
+		}
+	}
+
+	return matched
+}
+
+// checkCursor rejects the cursors made for another sort than the one of filter,
+// as the Postgres queries do.
+func checkCursor(filter helpers.Filter) error {
+
+	cursor := filter.Cursor
+
+	if cursor != nil &&
+		(cursor.Sort != filter.Sort || strings.EqualFold(cursor.Dir, "desc") != strings.EqualFold(filter.Dir, "desc")) {
+		return errors.Wrap(helpers.ErrInvalidFilter, "the cursor was made for another sort")
+	}
+
+	return nil
+}
+
+// matches reports whether name contains the search of filter, ignoring case.
+func matches(name string, filter helpers.Filter) bool {
+	return strings.Contains(strings.ToLower(name), strings.ToLower(filter.Search))
+}
+
+// window returns the bounds of the rows of a list of n sorted rows that the
+// limit and offset of filter keep. The offset is ignored with a cursor.
+func window(n int, filter helpers.Filter) (start, end int) {
+
+	if filter.Cursor != nil {
+		filter.Offset = 0
+	}
+
+	if filter.Offset >= n || filter.Limit <= 0 {
+		return 0, 0
+	}
+
+	end = filter.Offset + filter.Limit
+
+	if end > n {
+		end = n
+	}
+
+	return filter.Offset, end
+}
+
+func now() time.Time {
+	return time.Now().UTC()
+}
+
+func (s memoryContinent) GetOne(ctx context.Context, id uuid.UUID) (models.ContinentModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	continent, ok := s.m.continents[id]
+
+	if !ok {
+		return models.ContinentModel{}, sql.ErrNoRows
+	}
+
+	return continent, nil
+}
+
+func (s memoryContinent) GetOneByName(ctx context.Context, name string) (models.ContinentModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	for _, continent := range s.m.continents {
+		if strings.EqualFold(continent.Name, name) {
+			return continent, nil
+		}
+	}
+
+	return models.ContinentModel{}, sql.ErrNoRows
+}
+
+func (s memoryContinent) GetAll(ctx context.Context, filter helpers.Filter) ([]models.ContinentModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var (
+		continents []models.ContinentModel
+		names      []string
+		keys       []string
+	)
+	for _, continent := range s.m.continents {
+		continents = append(continents, continent)
+		names = append(names, continent.Name)
+		keys = append(keys, continent.Id.String())
+	}
+
+	indexes, err := page(names, keys, filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var paged []models.ContinentModel
+	for _, i := range indexes {
+		paged = append(paged, continents[i])
+	}
+
+	return paged, nil
+}
+
+func (s memoryContinent) Count(ctx context.Context, filter helpers.Filter) (int, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var names []string
+	for _, continent := range s.m.continents {
+		names = append(names, continent.Name)
+	}
+
+	return len(matching(names, filter)), nil
+}
+
+func (s memoryContinent) Insert(ctx context.Context, continent *models.ContinentModel) error {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	continent.Id = uuid.NewV4()
+	continent.CreatedAt = now()
+	s.m.continents[continent.Id] = *continent
+
+	return nil
+}
+
+func (s memorySubscription) GetOne(ctx context.Context, id uuid.UUID) (models.SubscriptionModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	subscription, ok := s.m.subscriptions[id]
+
+	if !ok {
+		return models.SubscriptionModel{}, sql.ErrNoRows
+	}
+
+	return subscription, nil
+}
+
+func (s memorySubscription) GetAll(ctx context.Context) ([]models.SubscriptionModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var subscriptions []models.SubscriptionModel
+	for _, subscription := range s.m.subscriptions {
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+	})
+
+	return subscriptions, nil
+}
+
+func (s memorySubscription) Insert(ctx context.Context, subscription *models.SubscriptionModel) error {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	subscription.Id = uuid.NewV4()
+	subscription.CreatedAt = now()
+	s.m.subscriptions[subscription.Id] = *subscription
+
+	return nil
+}
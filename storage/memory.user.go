@@ -0,0 +1,307 @@
+package storage
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"database/sql"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+	"sort"
+)
+
+type (
+	memoryUser struct {
+		m *memory
+	}
+
+	memoryToken struct {
+		m *memory
+	}
+
+	memoryRateLimit struct {
+		m *memory
+	}
+)
+
+// userDetail joins the user with its subscription. The caller holds m.mu.
+func (m *memory) userDetail(id uuid.UUID) (models.UserDetailModel, error) {
+
+	user, ok := m.users[id]
+
+	if !ok {
+		return models.UserDetailModel{}, sql.ErrNoRows
+	}
+
+	subscription, ok := m.subscriptions[user.SubscriptionId]
+
+	if !ok {
+		return models.UserDetailModel{}, sql.ErrNoRows
+	}
+
+	return models.UserDetailModel{User: user, Subscription: subscription}, nil
+}
+
+func (s memoryUser) GetOne(ctx context.Context, id uuid.UUID) (models.UserDetailModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	return s.m.userDetail(id)
+}
+
+func (s memoryUser) GetOneByEmail(ctx context.Context, email string) (models.UserDetailModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	for id, user := range s.m.users {
+		if user.Email == email {
+			return s.m.userDetail(id)
+		}
+	}
+
+	return models.UserDetailModel{}, sql.ErrNoRows
+}
+
+func (s memoryUser) GetAll(ctx context.Context, filter helpers.Filter) ([]models.UserDetailModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var (
+		users []models.UserDetailModel
+		names []string
+		keys  []string
+	)
+	for id, user := range s.m.users {
+
+		detail, err := s.m.userDetail(id)
+
+		if err != nil {
+			continue
+		}
+
+		users = append(users, detail)
+		names = append(names, user.Name)
+		keys = append(keys, id.String())
+	}
+
+	indexes, err := page(names, keys, filter)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var paged []models.UserDetailModel
+	for _, i := range indexes {
+		paged = append(paged, users[i])
+	}
+
+	return paged, nil
+}
+
+func (s memoryUser) Count(ctx context.Context, filter helpers.Filter) (int, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var names []string
+	for id, user := range s.m.users {
+		if _, err := s.m.userDetail(id); err == nil {
+			names = append(names, user.Name)
+		}
+	}
+
+	return len(matching(names, filter)), nil
+}
+
+func (s memoryUser) Insert(ctx context.Context, user *models.UserModel) error {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	for _, existing := range s.m.users {
+		if existing.Email == user.Email {
+			return errors.Errorf("user %q already exists", user.Email)
+		}
+	}
+
+	user.Id = uuid.NewV4()
+	user.CreatedAt = now()
+	user.IsActive = true
+	s.m.users[user.Id] = *user
+
+	return nil
+}
+
+func (s memoryToken) GetOne(ctx context.Context, id uuid.UUID) (models.TokenDetailModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	token, ok := s.m.tokens[id]
+
+	if !ok {
+		return models.TokenDetailModel{}, sql.ErrNoRows
+	}
+
+	user, err := s.m.userDetail(token.UserId)
+
+	if err != nil {
+		return models.TokenDetailModel{}, err
+	}
+
+	return models.TokenDetailModel{Token: token, User: user}, nil
+}
+
+func (s memoryToken) GetOneByTokenKey(ctx context.Context, tokenKey string) (models.TokenModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	for _, token := range s.m.tokens {
+		if token.TokenKey == tokenKey {
+			return token, nil
+		}
+	}
+
+	return models.TokenModel{}, sql.ErrNoRows
+}
+
+func (s memoryToken) GetAll(ctx context.Context) ([]models.TokenDetailModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var tokens []models.TokenDetailModel
+	for _, token := range s.m.tokens {
+
+		user, err := s.m.userDetail(token.UserId)
+
+		if err != nil {
+			continue
+		}
+
+		tokens = append(tokens, models.TokenDetailModel{Token: token, User: user})
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].Token.CreatedAt.Before(tokens[j].Token.CreatedAt)
+	})
+
+	return tokens, nil
+}
+
+func (s memoryToken) Insert(ctx context.Context, token *models.TokenModel) error {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	for _, existing := range s.m.tokens {
+		if existing.TokenKey == token.TokenKey {
+			return errors.New("token key already exists")
+		}
+	}
+
+	token.Id = uuid.NewV4()
+	token.CreatedAt = now()
+	token.IsActive = true
+	s.m.tokens[token.Id] = *token
+
+	return nil
+}
+
+func (s memoryRateLimit) GetOne(ctx context.Context, id uuid.UUID) (models.RateLimitDetailModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	limit, ok := s.m.rateLimits[id]
+
+	if !ok {
+		return models.RateLimitDetailModel{}, sql.ErrNoRows
+	}
+
+	user, err := s.m.userDetail(limit.UserId)
+
+	if err != nil {
+		return models.RateLimitDetailModel{}, err
+	}
+
+	return models.RateLimitDetailModel{RateLimit: limit, User: user}, nil
+}
+
+func (s memoryRateLimit) GetOneByUserId(ctx context.Context, userId uuid.UUID) (models.RateLimitModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	for _, limit := range s.m.rateLimits {
+		if limit.UserId == userId {
+			return limit, nil
+		}
+	}
+
+	return models.RateLimitModel{}, sql.ErrNoRows
+}
+
+func (s memoryRateLimit) GetAll(ctx context.Context) ([]models.RateLimitDetailModel, error) {
+
+	s.m.mu.RLock()
+	defer s.m.mu.RUnlock()
+
+	var limits []models.RateLimitDetailModel
+	for _, limit := range s.m.rateLimits {
+
+		user, err := s.m.userDetail(limit.UserId)
+
+		if err != nil {
+			continue
+		}
+
+		limits = append(limits, models.RateLimitDetailModel{RateLimit: limit, User: user})
+	}
+
+	sort.Slice(limits, func(i, j int) bool {
+		return limits[i].RateLimit.CreatedAt.Before(limits[j].RateLimit.CreatedAt)
+	})
+
+	return limits, nil
+}
+
+func (s memoryRateLimit) Insert(ctx context.Context, limit *models.RateLimitModel) error {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	limit.Id = uuid.NewV4()
+	limit.CreatedAt = now()
+	s.m.rateLimits[limit.Id] = *limit
+
+	return nil
+}
+
+func (s memoryRateLimit) Update(ctx context.Context, limit *models.RateLimitModel) error {
+
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+
+	existing, ok := s.m.rateLimits[limit.Id]
+
+	if !ok {
+		return sql.ErrNoRows
+	}
+
+	existing.UserId = limit.UserId
+	existing.TotalRequest = limit.TotalRequest
+	existing.UpdatedBy = limit.UpdatedBy
+	existing.UpdatedAt.Time, existing.UpdatedAt.Valid = now(), true
+	s.m.rateLimits[limit.Id] = existing
+
+	limit.CreatedAt = existing.CreatedAt
+	limit.CreatedBy = existing.CreatedBy
+	limit.UpdatedAt = existing.UpdatedAt
+
+	return nil
+}
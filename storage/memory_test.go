@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"database/sql"
+	"github.com/pkg/errors"
+	"testing"
+	"time"
+)
+
+func TestMemoryTransaction(t *testing.T) {
+
+	ctx := context.Background()
+	store := NewMemory()
+
+	continent := models.ContinentModel{Name: "Europe", Code: "EU"}
+	if err := store.Continent.Insert(ctx, &continent); err != nil {
+		t.Fatal(err)
+	}
+
+	country := models.CountryModel{ContinentId: continent.Id, Name: "Germany", Code: "DE"}
+	if err := store.Country.Insert(ctx, &country); err != nil {
+		t.Fatal(err)
+	}
+
+	data := models.CoronaModel{CountryId: country.Id, TotalCases: sql.NullInt64{Int64: 10, Valid: true}}
+
+	failed := errors.New("failed")
+	err := store.Transaction(ctx, func(tx Store) error {
+
+		if _, err := tx.Corona.Upsert(ctx, &data); err != nil {
+			return err
+		}
+
+		return failed
+	})
+
+	if err != failed {
+		t.Fatalf("got error %v, want %v", err, failed)
+	}
+
+	if _, err := store.Corona.GetByCountry(ctx, "germany"); err != sql.ErrNoRows {
+		t.Fatalf("rolled back figures are still stored: %v", err)
+	}
+
+	err = store.Transaction(ctx, func(tx Store) error {
+		_, err := tx.Corona.Upsert(ctx, &data)
+		return err
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detail, err := store.Corona.GetByCountry(ctx, "germany")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if detail.Country.Continent.Name != "Europe" || detail.Corona.TotalCases.Int64 != 10 {
+		t.Errorf("unexpected detail: %+v", detail)
+	}
+
+	same := models.CoronaModel{CountryId: country.Id, TotalCases: sql.NullInt64{Int64: 10, Valid: true}}
+	if result, _ := store.Corona.Upsert(ctx, &same); result != models.UpsertUnchanged {
+		t.Errorf("got %s for the same figures, want %s", result, models.UpsertUnchanged)
+	}
+
+	more := models.CoronaModel{CountryId: country.Id, TotalCases: sql.NullInt64{Int64: 12, Valid: true}}
+	if result, _ := store.Corona.Upsert(ctx, &more); result != models.UpsertUpdated {
+		t.Errorf("got %s for new figures, want %s", result, models.UpsertUpdated)
+	}
+
+	datas, _ := store.Corona.GetAll(ctx, helpers.Filter{FilterOption: helpers.FilterOption{Limit: 10}})
+	if len(datas) != 1 {
+		t.Errorf("got %d rows, want 1", len(datas))
+	}
+
+	again := models.CoronaModel{CountryId: country.Id}
+	if err := store.Corona.Insert(ctx, &again); errors.Cause(err) != helpers.ErrDuplicate {
+		t.Errorf("inserting figures of a country twice got %v, want %v", err, helpers.ErrDuplicate)
+	}
+}
+
+func TestMemorySnapshotDays(t *testing.T) {
+
+	ctx := context.Background()
+	store := NewMemory()
+
+	continent := models.ContinentModel{Name: "Europe"}
+	if err := store.Continent.Insert(ctx, &continent); err != nil {
+		t.Fatal(err)
+	}
+
+	country := models.CountryModel{ContinentId: continent.Id, Name: "Germany"}
+	if err := store.Country.Insert(ctx, &country); err != nil {
+		t.Fatal(err)
+	}
+
+	yesterday := time.Date(2020, 4, 1, 18, 0, 0, 0, time.UTC)
+	today := yesterday.AddDate(0, 0, 1)
+
+	for _, run := range []struct {
+		at    time.Time
+		cases int64
+	}{
+		{yesterday, 100},
+		{today, 150},
+		{today.Add(time.Hour), 160},
+	} {
+		snapshot := models.CoronaSnapshotModel{
+			CountryId:  country.Id,
+			ReportDate: run.at,
+			TotalCases: sql.NullInt64{Int64: run.cases, Valid: true},
+		}
+		if err := store.Corona.UpsertSnapshot(ctx, &snapshot); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snapshots, err := store.Corona.GetAllSnapshotByCountry(ctx, country.Id, yesterday.AddDate(0, 0, -1), today)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A new day adds a row and keeps yesterday's, a second run the same day
+	// replaces that day's figures.
+	if len(snapshots) != 2 || snapshots[0].TotalCases.Int64 != 100 || snapshots[1].TotalCases.Int64 != 160 {
+		t.Errorf("got snapshots %+v", snapshots)
+	}
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+type postgresCorona struct {
+	db helpers.Querier
+}
+
+func (s postgresCorona) GetByCountry(ctx context.Context, country string) (models.CoronaDetailModel, error) {
+
+	data, err := models.GetCoronaByCountry(ctx, s.db, country)
+
+	if err != nil {
+		return models.CoronaDetailModel{}, err
+	}
+
+	detail, err := postgresCountry{db: s.db}.GetOne(ctx, data.CountryId)
+
+	if err != nil {
+		return models.CoronaDetailModel{}, err
+	}
+
+	return models.CoronaDetailModel{Corona: data, Country: detail}, nil
+}
+
+func (s postgresCorona) GetAll(ctx context.Context, filter helpers.Filter) ([]models.CoronaDetailModel, error) {
+	return models.GetAllCorona(ctx, s.db, filter)
+}
+
+func (s postgresCorona) GetAllByContinent(ctx context.Context, filter helpers.Filter, continentId uuid.UUID) (
+	[]models.CoronaDetailModel, error) {
+	return models.GetAllCoronaByContinent(ctx, s.db, filter, continentId)
+}
+
+func (s postgresCorona) GetAllByCountries(ctx context.Context, countryIds []uuid.UUID) (
+	[]models.CoronaDetailModel, error) {
+	return models.GetAllCoronaByCountries(ctx, s.db, countryIds)
+}
+
+func (s postgresCorona) Count(ctx context.Context, filter helpers.Filter) (int, error) {
+	return models.CountCorona(ctx, s.db, filter)
+}
+
+func (s postgresCorona) CountByContinent(ctx context.Context, filter helpers.Filter, continentId uuid.UUID) (int, error) {
+	return models.CountCoronaByContinent(ctx, s.db, filter, continentId)
+}
+
+func (s postgresCorona) GetTop(ctx context.Context, metric string, limit int, continentId uuid.NullUUID) (
+	[]models.CoronaDetailModel, error) {
+	return models.GetTopCorona(ctx, s.db, metric, limit, continentId)
+}
+
+func (s postgresCorona) Insert(ctx context.Context, data *models.CoronaModel) error {
+	return data.Insert(ctx, s.db)
+}
+
+func (s postgresCorona) Upsert(ctx context.Context, data *models.CoronaModel) (models.UpsertResult, error) {
+	return data.Upsert(ctx, s.db)
+}
+
+func (s postgresCorona) GetTotalByContinent(ctx context.Context, continentId uuid.UUID) (
+	models.CoronaTotalModel, error) {
+	return models.GetCoronaTotalByContinent(ctx, s.db, continentId)
+}
+
+func (s postgresCorona) GetAllTotal(ctx context.Context) ([]models.CoronaTotalModel, error) {
+	return models.GetAllCoronaTotal(ctx, s.db)
+}
+
+func (s postgresCorona) GetAllSnapshotByCountry(ctx context.Context, countryId uuid.UUID, from, to time.Time) (
+	[]models.CoronaSnapshotModel, error) {
+	return models.GetAllCoronaSnapshotByCountry(ctx, s.db, countryId, from, to)
+}
+
+func (s postgresCorona) UpsertSnapshot(ctx context.Context, snapshot *models.CoronaSnapshotModel) error {
+	return snapshot.Upsert(ctx, s.db)
+}
+
+func (s postgresCorona) GetLatestWorldSummary(ctx context.Context) (models.WorldSummaryModel, error) {
+	return models.GetLatestWorldSummary(ctx, s.db)
+}
+
+func (s postgresCorona) UpsertWorldSummary(ctx context.Context, summary *models.WorldSummaryModel) error {
+	return summary.Upsert(ctx, s.db)
+}
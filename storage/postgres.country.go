@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	uuid "github.com/satori/go.uuid"
+)
+
+type postgresCountry struct {
+	db helpers.Querier
+}
+
+func (s postgresCountry) GetOne(ctx context.Context, id uuid.UUID) (models.CountryDetailModel, error) {
+
+	country, err := models.GetOneCountry(ctx, s.db, id)
+
+	if err != nil {
+		return models.CountryDetailModel{}, err
+	}
+
+	return s.detail(ctx, country)
+}
+
+func (s postgresCountry) GetOneByName(ctx context.Context, name string) (models.CountryDetailModel, error) {
+
+	country, err := models.GetOneCountryByName(ctx, s.db, name)
+
+	if err != nil {
+		return models.CountryDetailModel{}, err
+	}
+
+	return s.detail(ctx, country)
+}
+
+func (s postgresCountry) GetOneByCode(ctx context.Context, code string) (models.CountryDetailModel, error) {
+
+	country, err := models.GetOneCountryByCode(ctx, s.db, code)
+
+	if err != nil {
+		return models.CountryDetailModel{}, err
+	}
+
+	return s.detail(ctx, country)
+}
+
+func (s postgresCountry) detail(ctx context.Context, country models.CountryModel) (models.CountryDetailModel, error) {
+
+	continent, err := models.GetOneContinent(ctx, s.db, country.ContinentId)
+
+	if err != nil {
+		return models.CountryDetailModel{}, err
+	}
+
+	return models.CountryDetailModel{Country: country, Continent: continent}, nil
+}
+
+func (s postgresCountry) GetAll(ctx context.Context, filter helpers.Filter) ([]models.CountryDetailModel, error) {
+	return models.GetAllCountries(ctx, s.db, filter)
+}
+
+func (s postgresCountry) Count(ctx context.Context, filter helpers.Filter) (int, error) {
+	return models.CountCountries(ctx, s.db, filter)
+}
+
+func (s postgresCountry) All(ctx context.Context) ([]models.CountryModel, error) {
+	return models.GetAllCountry(ctx, s.db)
+}
+
+func (s postgresCountry) Insert(ctx context.Context, country *models.CountryModel) error {
+	return country.Insert(ctx, s.db)
+}
+
+func (s postgresCountry) GetOneAlias(ctx context.Context, id uuid.UUID) (models.CountryAliasModel, error) {
+	return models.GetOneCountryAlias(ctx, s.db, id)
+}
+
+func (s postgresCountry) GetAllAliasByCountry(ctx context.Context, countryId uuid.UUID) (
+	[]models.CountryAliasModel, error) {
+	return models.GetAllCountryAliasByCountry(ctx, s.db, countryId)
+}
+
+func (s postgresCountry) GetAllAliasBySource(ctx context.Context, source string) ([]models.CountryAliasModel, error) {
+	return models.GetAllCountryAliasBySource(ctx, s.db, source)
+}
+
+func (s postgresCountry) InsertAlias(ctx context.Context, alias *models.CountryAliasModel) error {
+	return alias.Insert(ctx, s.db)
+}
+
+func (s postgresCountry) DeleteAlias(ctx context.Context, alias *models.CountryAliasModel) error {
+	return alias.Delete(ctx, s.db)
+}
+
+func (s postgresCountry) GetOneUnmatchedRow(ctx context.Context, id uuid.UUID) (models.UnmatchedScrapeRowModel, error) {
+	return models.GetOneUnmatchedScrapeRow(ctx, s.db, id)
+}
+
+func (s postgresCountry) GetAllUnmatchedRow(ctx context.Context, resolved bool) (
+	[]models.UnmatchedScrapeRowModel, error) {
+	return models.GetAllUnmatchedScrapeRow(ctx, s.db, resolved)
+}
+
+func (s postgresCountry) UpsertUnmatchedRow(ctx context.Context, row *models.UnmatchedScrapeRowModel) error {
+	return row.Upsert(ctx, s.db)
+}
+
+func (s postgresCountry) ResolveUnmatchedRow(ctx context.Context, row *models.UnmatchedScrapeRowModel) error {
+	return row.Resolve(ctx, s.db)
+}
@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	"database/sql"
+	uuid "github.com/satori/go.uuid"
+)
+
+type (
+	postgresContinent struct {
+		db helpers.Querier
+	}
+
+	postgresSubscription struct {
+		db helpers.Querier
+	}
+)
+
+// NewPostgres returns a Store running the models' queries against db.
+func NewPostgres(db *sql.DB) Store {
+
+	store := newPostgres(db)
+	store.transaction = func(ctx context.Context, fn func(store Store) error) error {
+		return helpers.WithTransaction(ctx, db, func(tx *sql.Tx) error {
+			txStore := newPostgres(tx)
+			txStore.transaction = func(ctx context.Context, fn func(store Store) error) error {
+				return fn(txStore)
+			}
+			return fn(txStore)
+		})
+	}
+
+	return store
+}
+
+func newPostgres(db helpers.Querier) Store {
+
+	return Store{
+		Continent:    postgresContinent{db: db},
+		Country:      postgresCountry{db: db},
+		Corona:       postgresCorona{db: db},
+		User:         postgresUser{db: db},
+		Token:        postgresToken{db: db},
+		RateLimit:    postgresRateLimit{db: db},
+		Subscription: postgresSubscription{db: db},
+	}
+}
+
+func (s postgresContinent) GetOne(ctx context.Context, id uuid.UUID) (models.ContinentModel, error) {
+	return models.GetOneContinent(ctx, s.db, id)
+}
+
+func (s postgresContinent) GetOneByName(ctx context.Context, name string) (models.ContinentModel, error) {
+	return models.GetOneContinentByName(ctx, s.db, name)
+}
+
+func (s postgresContinent) GetAll(ctx context.Context, filter helpers.Filter) ([]models.ContinentModel, error) {
+	return models.GetAllContinent(ctx, s.db, filter)
+}
+
+func (s postgresContinent) Count(ctx context.Context, filter helpers.Filter) (int, error) {
+	return models.CountContinent(ctx, s.db, filter)
+}
+
+func (s postgresContinent) Insert(ctx context.Context, continent *models.ContinentModel) error {
+	return continent.Insert(ctx, s.db)
+}
+
+func (s postgresSubscription) GetOne(ctx context.Context, id uuid.UUID) (models.SubscriptionModel, error) {
+	return models.GetOneSubscription(ctx, s.db, id)
+}
+
+func (s postgresSubscription) GetAll(ctx context.Context) ([]models.SubscriptionModel, error) {
+	return models.GetAllSubscription(ctx, s.db)
+}
+
+func (s postgresSubscription) Insert(ctx context.Context, subscription *models.SubscriptionModel) error {
+	return subscription.Insert(ctx, s.db)
+}
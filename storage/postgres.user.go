@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	uuid "github.com/satori/go.uuid"
+)
+
+type (
+	postgresUser struct {
+		db helpers.Querier
+	}
+
+	postgresToken struct {
+		db helpers.Querier
+	}
+
+	postgresRateLimit struct {
+		db helpers.Querier
+	}
+)
+
+func (s postgresUser) GetOne(ctx context.Context, id uuid.UUID) (models.UserDetailModel, error) {
+
+	user, err := models.GetOneUser(ctx, s.db, id)
+
+	if err != nil {
+		return models.UserDetailModel{}, err
+	}
+
+	return s.detail(ctx, user)
+}
+
+func (s postgresUser) GetOneByEmail(ctx context.Context, email string) (models.UserDetailModel, error) {
+
+	user, err := models.GetOneUserByEmail(ctx, s.db, email)
+
+	if err != nil {
+		return models.UserDetailModel{}, err
+	}
+
+	return s.detail(ctx, user)
+}
+
+func (s postgresUser) detail(ctx context.Context, user models.UserModel) (models.UserDetailModel, error) {
+
+	subscription, err := models.GetOneSubscription(ctx, s.db, user.SubscriptionId)
+
+	if err != nil {
+		return models.UserDetailModel{}, err
+	}
+
+	return models.UserDetailModel{User: user, Subscription: subscription}, nil
+}
+
+func (s postgresUser) GetAll(ctx context.Context, filter helpers.Filter) ([]models.UserDetailModel, error) {
+	return models.GetAllUser(ctx, s.db, filter)
+}
+
+func (s postgresUser) Count(ctx context.Context, filter helpers.Filter) (int, error) {
+	return models.CountUser(ctx, s.db, filter)
+}
+
+func (s postgresUser) Insert(ctx context.Context, user *models.UserModel) error {
+	return user.Insert(ctx, s.db)
+}
+
+func (s postgresToken) GetOne(ctx context.Context, id uuid.UUID) (models.TokenDetailModel, error) {
+
+	token, err := models.GetOneToken(ctx, s.db, id)
+
+	if err != nil {
+		return models.TokenDetailModel{}, err
+	}
+
+	user, err := postgresUser{db: s.db}.GetOne(ctx, token.UserId)
+
+	if err != nil {
+		return models.TokenDetailModel{}, err
+	}
+
+	return models.TokenDetailModel{Token: token, User: user}, nil
+}
+
+func (s postgresToken) GetOneByTokenKey(ctx context.Context, tokenKey string) (models.TokenModel, error) {
+	return models.GetOneTokenByTokenKey(ctx, s.db, tokenKey)
+}
+
+func (s postgresToken) GetAll(ctx context.Context) ([]models.TokenDetailModel, error) {
+	return models.GetAllToken(ctx, s.db)
+}
+
+func (s postgresToken) Insert(ctx context.Context, token *models.TokenModel) error {
+	return token.Insert(ctx, s.db)
+}
+
+func (s postgresRateLimit) GetOne(ctx context.Context, id uuid.UUID) (models.RateLimitDetailModel, error) {
+
+	limit, err := models.GetOneRateLimit(ctx, s.db, id)
+
+	if err != nil {
+		return models.RateLimitDetailModel{}, err
+	}
+
+	user, err := postgresUser{db: s.db}.GetOne(ctx, limit.UserId)
+
+	if err != nil {
+		return models.RateLimitDetailModel{}, err
+	}
+
+	return models.RateLimitDetailModel{RateLimit: limit, User: user}, nil
+}
+
+func (s postgresRateLimit) GetOneByUserId(ctx context.Context, userId uuid.UUID) (models.RateLimitModel, error) {
+	return models.GetOneRateLimitByUserId(ctx, s.db, userId)
+}
+
+func (s postgresRateLimit) GetAll(ctx context.Context) ([]models.RateLimitDetailModel, error) {
+	return models.GetAllRateLimit(ctx, s.db)
+}
+
+func (s postgresRateLimit) Insert(ctx context.Context, limit *models.RateLimitModel) error {
+	return limit.Insert(ctx, s.db)
+}
+
+func (s postgresRateLimit) Update(ctx context.Context, limit *models.RateLimitModel) error {
+	return limit.Update(ctx, s.db)
+}
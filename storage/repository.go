@@ -0,0 +1,129 @@
+// Package storage defines a repository per aggregate and the Store bundling
+// them. NewPostgres backs them with the models' queries, NewMemory with maps so
+// modules, handlers and cron tasks can run in tests without a database.
+//
+// Lookups of a missing row return sql.ErrNoRows whatever the implementation.
+package storage
+
+import (
+	"context"
+	"corona/helpers"
+	"corona/models"
+	uuid "github.com/satori/go.uuid"
+	"time"
+)
+
+type (
+	ContinentRepository interface {
+		GetOne(ctx context.Context, id uuid.UUID) (models.ContinentModel, error)
+		GetOneByName(ctx context.Context, name string) (models.ContinentModel, error)
+		GetAll(ctx context.Context, filter helpers.Filter) ([]models.ContinentModel, error)
+		// Count returns how many rows GetAll pages through for filter.
+		Count(ctx context.Context, filter helpers.Filter) (int, error)
+		Insert(ctx context.Context, continent *models.ContinentModel) error
+	}
+
+	// CountryRepository also holds the names countries are published under: the
+	// aliases and the scraped rows that are still waiting for one.
+	CountryRepository interface {
+		GetOne(ctx context.Context, id uuid.UUID) (models.CountryDetailModel, error)
+		GetOneByName(ctx context.Context, name string) (models.CountryDetailModel, error)
+		// GetOneByCode looks the country up by its ISO alpha-2 or alpha-3 code.
+		GetOneByCode(ctx context.Context, code string) (models.CountryDetailModel, error)
+		GetAll(ctx context.Context, filter helpers.Filter) ([]models.CountryDetailModel, error)
+		Count(ctx context.Context, filter helpers.Filter) (int, error)
+		// All returns every country, unpaged and without its continent.
+		All(ctx context.Context) ([]models.CountryModel, error)
+		Insert(ctx context.Context, country *models.CountryModel) error
+
+		GetOneAlias(ctx context.Context, id uuid.UUID) (models.CountryAliasModel, error)
+		GetAllAliasByCountry(ctx context.Context, countryId uuid.UUID) ([]models.CountryAliasModel, error)
+		GetAllAliasBySource(ctx context.Context, source string) ([]models.CountryAliasModel, error)
+		InsertAlias(ctx context.Context, alias *models.CountryAliasModel) error
+		DeleteAlias(ctx context.Context, alias *models.CountryAliasModel) error
+
+		GetOneUnmatchedRow(ctx context.Context, id uuid.UUID) (models.UnmatchedScrapeRowModel, error)
+		GetAllUnmatchedRow(ctx context.Context, resolved bool) ([]models.UnmatchedScrapeRowModel, error)
+		UpsertUnmatchedRow(ctx context.Context, row *models.UnmatchedScrapeRowModel) error
+		ResolveUnmatchedRow(ctx context.Context, row *models.UnmatchedScrapeRowModel) error
+	}
+
+	// CoronaRepository holds the latest figures of every country along with their
+	// daily snapshots, the continent totals and the world summaries.
+	CoronaRepository interface {
+		GetByCountry(ctx context.Context, country string) (models.CoronaDetailModel, error)
+		GetAll(ctx context.Context, filter helpers.Filter) ([]models.CoronaDetailModel, error)
+		GetAllByContinent(ctx context.Context, filter helpers.Filter, continentId uuid.UUID) (
+			[]models.CoronaDetailModel, error)
+		// GetAllByCountries returns the figures of the countries of countryIds that
+		// have some, by country name.
+		GetAllByCountries(ctx context.Context, countryIds []uuid.UUID) ([]models.CoronaDetailModel, error)
+		Count(ctx context.Context, filter helpers.Filter) (int, error)
+		CountByContinent(ctx context.Context, filter helpers.Filter, continentId uuid.UUID) (int, error)
+		// GetTop ranks the countries, of a continent when continentId is set, by the
+		// value of metric, highest first, leaving out those for which it is unknown.
+		GetTop(ctx context.Context, metric string, limit int, continentId uuid.NullUUID) (
+			[]models.CoronaDetailModel, error)
+		Insert(ctx context.Context, data *models.CoronaModel) error
+		Upsert(ctx context.Context, data *models.CoronaModel) (models.UpsertResult, error)
+
+		GetTotalByContinent(ctx context.Context, continentId uuid.UUID) (models.CoronaTotalModel, error)
+		GetAllTotal(ctx context.Context) ([]models.CoronaTotalModel, error)
+
+		GetAllSnapshotByCountry(ctx context.Context, countryId uuid.UUID, from, to time.Time) (
+			[]models.CoronaSnapshotModel, error)
+		// UpsertSnapshot adds the figures of a day. Snapshots of other days are kept
+		// as they are; a later run on the same day replaces that day's figures.
+		UpsertSnapshot(ctx context.Context, snapshot *models.CoronaSnapshotModel) error
+
+		GetLatestWorldSummary(ctx context.Context) (models.WorldSummaryModel, error)
+		UpsertWorldSummary(ctx context.Context, summary *models.WorldSummaryModel) error
+	}
+
+	UserRepository interface {
+		GetOne(ctx context.Context, id uuid.UUID) (models.UserDetailModel, error)
+		GetOneByEmail(ctx context.Context, email string) (models.UserDetailModel, error)
+		GetAll(ctx context.Context, filter helpers.Filter) ([]models.UserDetailModel, error)
+		Count(ctx context.Context, filter helpers.Filter) (int, error)
+		Insert(ctx context.Context, user *models.UserModel) error
+	}
+
+	TokenRepository interface {
+		GetOne(ctx context.Context, id uuid.UUID) (models.TokenDetailModel, error)
+		GetOneByTokenKey(ctx context.Context, tokenKey string) (models.TokenModel, error)
+		GetAll(ctx context.Context) ([]models.TokenDetailModel, error)
+		Insert(ctx context.Context, token *models.TokenModel) error
+	}
+
+	RateLimitRepository interface {
+		GetOne(ctx context.Context, id uuid.UUID) (models.RateLimitDetailModel, error)
+		GetOneByUserId(ctx context.Context, userId uuid.UUID) (models.RateLimitModel, error)
+		GetAll(ctx context.Context) ([]models.RateLimitDetailModel, error)
+		Insert(ctx context.Context, limit *models.RateLimitModel) error
+		Update(ctx context.Context, limit *models.RateLimitModel) error
+	}
+
+	SubscriptionRepository interface {
+		GetOne(ctx context.Context, id uuid.UUID) (models.SubscriptionModel, error)
+		GetAll(ctx context.Context) ([]models.SubscriptionModel, error)
+		Insert(ctx context.Context, subscription *models.SubscriptionModel) error
+	}
+
+	Store struct {
+		Continent    ContinentRepository
+		Country      CountryRepository
+		Corona       CoronaRepository
+		User         UserRepository
+		Token        TokenRepository
+		RateLimit    RateLimitRepository
+		Subscription SubscriptionRepository
+
+		transaction func(ctx context.Context, fn func(store Store) error) error
+	}
+)
+
+// Transaction runs fn with a Store whose writes are all kept when fn succeeds
+// and all discarded when it returns an error.
+func (s Store) Transaction(ctx context.Context, fn func(store Store) error) error {
+	return s.transaction(ctx, fn)
+}